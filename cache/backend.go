@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by a Backend when the key is missing or expired.
+var ErrNotFound = errors.New("cache: not found")
+
+// Backend is a byte store shared by cache tiers.
+// A ttl <= 0 means the value never expires.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+func expireAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func expired(at time.Time) bool {
+	return !at.IsZero() && time.Now().After(at)
+}
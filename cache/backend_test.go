@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testBackend(t *testing.T, b Backend) {
+	ctx := context.Background()
+	if _, err := b.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	if err := b.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if v, err := b.Get(ctx, "a"); err != nil || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v, want 1", v, err)
+	}
+	if err := b.Set(ctx, "a", []byte("2"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if v, err := b.Get(ctx, "a"); err != nil || string(v) != "2" {
+		t.Errorf("Get(a) after overwrite = %q, %v, want 2", v, err)
+	}
+
+	if err := b.Set(ctx, "short", []byte("x"), 5*time.Millisecond); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, err := b.Get(ctx, "short"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(short) after ttl error = %v, want ErrNotFound", err)
+	}
+
+	if err := b.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := b.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) after Delete() error = %v, want ErrNotFound", err)
+	}
+	if err := b.Delete(ctx, "a"); err != nil {
+		t.Errorf("Delete() of missing key error = %v", err)
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	testBackend(t, NewMemoryBackend())
+}
+
+func TestFileBackend(t *testing.T) {
+	dir := t.TempDir()
+	b, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBackend(t, b)
+
+	// values survive a new backend on the same directory
+	ctx := context.Background()
+	b.Set(ctx, "k", []byte("v"), time.Hour)
+	b.Set(ctx, "old", []byte("v"), time.Millisecond)
+	b2, _ := NewFileBackend(dir)
+	if v, err := b2.Get(ctx, "k"); err != nil || string(v) != "v" {
+		t.Errorf("Get(k) from reopened backend = %q, %v", v, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := b2.Purge(); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if _, err := b2.Get(ctx, "old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(old) after Purge() error = %v", err)
+	}
+}
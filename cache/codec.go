@@ -0,0 +1,10 @@
+package cache
+
+import "github.com/chenyan/wheels/codec"
+
+// Codec serializes the values stored in a Backend. Any codec.Codec fits,
+// e.g. codec.MsgPack or a codec looked up with codec.ForContentType.
+type Codec = codec.Codec
+
+// JSONCodec encodes values as JSON, the same encoding as codec.JSONString.
+var JSONCodec Codec = codec.JSON
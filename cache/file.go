@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileBackend is a Backend that keeps one file per key in a directory,
+// so the cached values survive restarts.
+// Each file holds the expiry time as 8 bytes of unix nanoseconds followed by the value.
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend creates a FileBackend in dir, creating the directory if needed.
+func NewFileBackend(dir string) (*FileBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %s %w", dir, err)
+	}
+	return &FileBackend{dir: dir}, nil
+}
+
+func (b *FileBackend) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(b.dir, hex.EncodeToString(sum[:]))
+}
+
+func (b *FileBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	bs, err := os.ReadFile(b.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	at, value, err := decodeFile(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file for key: %s %w", key, err)
+	}
+	if expired(at) {
+		os.Remove(b.path(key))
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// Set writes the value to a temporary file and renames it, so readers never see partial writes.
+func (b *FileBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f, err := os.CreateTemp(b.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	var header [8]byte
+	if at := expireAt(ttl); !at.IsZero() {
+		binary.BigEndian.PutUint64(header[:], uint64(at.UnixNano()))
+	}
+	if _, err = f.Write(header[:]); err == nil {
+		_, err = f.Write(value)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cache file for key: %s %w", key, err)
+	}
+	return os.Rename(f.Name(), b.path(key))
+}
+
+func (b *FileBackend) Delete(ctx context.Context, key string) error {
+	err := os.Remove(b.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Purge removes the expired files.
+func (b *FileBackend) Purge() error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := filepath.Join(b.dir, e.Name())
+		bs, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		if at, _, err := decodeFile(bs); err == nil && expired(at) {
+			os.Remove(name)
+		}
+	}
+	return nil
+}
+
+func decodeFile(bs []byte) (time.Time, []byte, error) {
+	if len(bs) < 8 {
+		return time.Time{}, nil, errors.New("truncated header")
+	}
+	var at time.Time
+	if ns := binary.BigEndian.Uint64(bs[:8]); ns != 0 {
+		at = time.Unix(0, int64(ns))
+	}
+	return at, bs[8:], nil
+}
@@ -0,0 +1,342 @@
+package lru
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/chenyan/wheels/cc"
+	"github.com/chenyan/wheels/funcs"
+)
+
+var (
+	// ErrNotFound is returned by Get on a miss when the cache has no loader.
+	ErrNotFound = errors.New("lru: not found")
+	// ErrLoaderPanic is returned by Get when the loader panics.
+	ErrLoaderPanic = errors.New("lru: loader panicked")
+)
+
+// Opts configures a Cache. The zero value is an unbounded cache without expiry.
+type Opts[K comparable, V any] struct {
+	MaxEntries int   // maximum number of entries, <= 0 means unlimited
+	MaxCost    int64 // maximum total cost, <= 0 means unlimited
+	// Cost returns the cost of an entry, e.g. its size in bytes. Defaults to 1 per entry.
+	Cost func(key K, value V) int64
+	// TTL is the time to live of entries stored without an explicit ttl, 0 means forever.
+	TTL time.Duration
+	// Loader loads missing keys in Get.
+	Loader func(ctx context.Context, key K) (V, error)
+	// OnEvict is called for entries removed to honor MaxEntries or MaxCost, or because they expired.
+	// It is called with the cache lock held and must not call back into the cache.
+	OnEvict func(key K, value V)
+}
+
+// Stats are the counters of a Cache.
+type Stats struct {
+	Hits        int64
+	Misses      int64
+	Evictions   int64 // entries removed to honor MaxEntries or MaxCost
+	Expirations int64 // entries removed because their ttl elapsed
+	Loads       int64 // calls to the loader
+	LoadErrors  int64
+}
+
+// HitRate returns Hits / (Hits + Misses).
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	cost     int64
+	expireAt time.Time // zero means never
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && now.After(e.expireAt)
+}
+
+// Cache is a concurrent LRU cache with optional cost bound, per-entry TTL and a loader.
+// Concurrent misses on the same key in Get are coalesced into a single load.
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	opts  Opts[K, V]
+	ll    *list.List // front is the most recently used
+	items map[K]*list.Element
+	cost  int64
+	stats Stats
+
+	flight cc.Group[K, V] // coalesces concurrent loads of the same key
+}
+
+// New creates a new Cache. opts may be nil.
+func New[K comparable, V any](opts *Opts[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	return c
+}
+
+// Load returns the value stored for key, if it is present and not expired.
+func (c *Cache[K, V]) Load(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e := c.lookup(key, time.Now()); e != nil {
+		c.stats.Hits++
+		return e.value, true
+	}
+	c.stats.Misses++
+	return value, false
+}
+
+// Get returns the value for key, loading it with the loader on a miss.
+// Concurrent misses on the same key share one loader call, which runs on a context
+// detached from the deadline of any single caller, see cc.Group.DoContext; each caller
+// still returns when its own ctx is done. Failed loads are not cached.
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	c.mu.Lock()
+	if e := c.lookup(key, time.Now()); e != nil {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.stats.Misses++
+	if c.opts.Loader == nil {
+		c.mu.Unlock()
+		var zero V
+		return zero, ErrNotFound
+	}
+	c.mu.Unlock()
+
+	v, err, _ := c.flight.DoContext(ctx, key, func(ctx context.Context) (V, error) {
+		c.mu.Lock()
+		// another load may have completed since the miss
+		if e := c.lookup(key, time.Now()); e != nil {
+			c.mu.Unlock()
+			return e.value, nil
+		}
+		c.stats.Loads++
+		c.mu.Unlock()
+
+		var v V
+		err := ErrLoaderPanic
+		funcs.F(func() { v, err = c.opts.Loader(ctx, key) })
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err != nil {
+			c.stats.LoadErrors++
+		} else {
+			c.store(key, v, c.opts.TTL)
+		}
+		return v, err
+	})
+	return v, err
+}
+
+// Store sets the value for key with the default TTL.
+func (c *Cache[K, V]) Store(key K, value V) {
+	c.StoreWithTTL(key, value, c.opts.TTL)
+}
+
+// StoreWithTTL sets the value for key, expiring after ttl. A ttl <= 0 never expires.
+func (c *Cache[K, V]) StoreWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(key, value, ttl)
+}
+
+// Delete removes key from the cache.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// LoadAndDelete removes key and returns its value, if it was present and not expired.
+func (c *Cache[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	e := c.remove(el)
+	if e.expired(time.Now()) {
+		return value, false
+	}
+	return e.value, true
+}
+
+// Len returns the number of entries, including expired ones not yet purged.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Cost returns the total cost of the entries.
+func (c *Cache[K, V]) Cost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cost
+}
+
+// Clear removes all entries without calling OnEvict.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.items)
+	c.cost = 0
+}
+
+// Purge removes the expired entries.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for el := c.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if e := el.Value.(*entry[K, V]); e.expired(now) {
+			c.expire(el)
+		}
+		el = prev
+	}
+}
+
+// Stats returns a copy of the counters.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Range calls f for each live entry from the most to the least recently used,
+// without updating recency. Like cc.Map.Range, f is called without holding the lock,
+// so it may observe a state that is no longer current.
+func (c *Cache[K, V]) Range(f func(key K, value V) bool) {
+	for _, e := range c.entries() {
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
+
+func (c *Cache[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		c.Range(func(key K, value V) bool {
+			return yield(key)
+		})
+	}
+}
+
+func (c *Cache[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		c.Range(func(key K, value V) bool {
+			return yield(value)
+		})
+	}
+}
+
+func (c *Cache[K, V]) Items() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.Range(func(key K, value V) bool {
+			return yield(key, value)
+		})
+	}
+}
+
+// entries copies the live entries in recency order.
+func (c *Cache[K, V]) entries() []entry[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	rv := make([]entry[K, V], 0, c.ll.Len())
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		if e := el.Value.(*entry[K, V]); !e.expired(now) {
+			rv = append(rv, *e)
+		}
+	}
+	return rv
+}
+
+// lookup returns the live entry for key and marks it as recently used.
+// Expired entries are removed. It must be called with c.mu held.
+func (c *Cache[K, V]) lookup(key K, now time.Time) *entry[K, V] {
+	el, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*entry[K, V])
+	if e.expired(now) {
+		c.expire(el)
+		return nil
+	}
+	c.ll.MoveToFront(el)
+	return e
+}
+
+// store must be called with c.mu held.
+func (c *Cache[K, V]) store(key K, value V, ttl time.Duration) {
+	var cost int64 = 1
+	if c.opts.Cost != nil {
+		cost = c.opts.Cost(key, value)
+	}
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		c.cost += cost - e.cost
+		e.value, e.cost, e.expireAt = value, cost, expireAt
+		c.ll.MoveToFront(el)
+	} else {
+		c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, cost: cost, expireAt: expireAt})
+		c.cost += cost
+	}
+	c.evict()
+}
+
+// evict drops the least recently used entries until the bounds are honored.
+// An entry whose cost alone exceeds MaxCost is evicted right away.
+func (c *Cache[K, V]) evict() {
+	for c.ll.Len() > 0 &&
+		((c.opts.MaxEntries > 0 && c.ll.Len() > c.opts.MaxEntries) ||
+			(c.opts.MaxCost > 0 && c.cost > c.opts.MaxCost)) {
+		e := c.remove(c.ll.Back())
+		c.stats.Evictions++
+		if c.opts.OnEvict != nil {
+			c.opts.OnEvict(e.key, e.value)
+		}
+	}
+}
+
+func (c *Cache[K, V]) expire(el *list.Element) {
+	e := c.remove(el)
+	c.stats.Expirations++
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(e.key, e.value)
+	}
+}
+
+func (c *Cache[K, V]) remove(el *list.Element) *entry[K, V] {
+	e := c.ll.Remove(el).(*entry[K, V])
+	delete(c.items, e.key)
+	c.cost -= e.cost
+	return e
+}
@@ -0,0 +1,218 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_MaxEntries(t *testing.T) {
+	var evicted []string
+	c := New(&Opts[string, int]{
+		MaxEntries: 2,
+		OnEvict:    func(k string, v int) { evicted = append(evicted, k) },
+	})
+	c.Store("a", 1)
+	c.Store("b", 2)
+	c.Load("a") // a is now more recent than b
+	c.Store("c", 3)
+
+	if _, ok := c.Load("b"); ok {
+		t.Error("Load(b) should miss after eviction")
+	}
+	if got := slices.Collect(c.Keys()); !slices.Equal(got, []string{"c", "a"}) {
+		t.Errorf("Keys() = %v, want [c a]", got)
+	}
+	if !slices.Equal(evicted, []string{"b"}) {
+		t.Errorf("evicted = %v, want [b]", evicted)
+	}
+	if st := c.Stats(); st.Hits != 1 || st.Misses != 1 || st.Evictions != 1 {
+		t.Errorf("Stats() = %+v", st)
+	}
+}
+
+func TestCache_MaxCost(t *testing.T) {
+	c := New(&Opts[string, string]{
+		MaxCost: 10,
+		Cost:    func(k, v string) int64 { return int64(len(v)) },
+	})
+	c.Store("a", "12345")
+	c.Store("b", "1234")
+	if c.Cost() != 9 || c.Len() != 2 {
+		t.Fatalf("Cost() = %d, Len() = %d, want 9, 2", c.Cost(), c.Len())
+	}
+	c.Store("c", "12")
+	if _, ok := c.Load("a"); ok {
+		t.Error("Load(a) should miss after cost eviction")
+	}
+	if c.Cost() != 6 {
+		t.Errorf("Cost() = %d, want 6", c.Cost())
+	}
+	c.Store("b", "1")
+	if c.Cost() != 3 {
+		t.Errorf("Cost() after overwrite = %d, want 3", c.Cost())
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	c := New(&Opts[string, int]{TTL: 10 * time.Millisecond})
+	c.Store("a", 1)
+	c.StoreWithTTL("b", 2, 0)
+	if v, ok := c.Load("a"); !ok || v != 1 {
+		t.Fatalf("Load(a) = %v, %v, want 1, true", v, ok)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := slices.Collect(c.Keys()); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Keys() = %v, want [b]", got)
+	}
+	if _, ok := c.Load("a"); ok {
+		t.Error("Load(a) should miss after ttl")
+	}
+	if st := c.Stats(); st.Expirations != 1 {
+		t.Errorf("Expirations = %d, want 1", st.Expirations)
+	}
+}
+
+func TestCache_Purge(t *testing.T) {
+	c := New[string, int](nil)
+	c.StoreWithTTL("a", 1, time.Millisecond)
+	c.Store("b", 2)
+	time.Sleep(5 * time.Millisecond)
+	c.Purge()
+	if c.Len() != 1 {
+		t.Errorf("Len() after Purge() = %d, want 1", c.Len())
+	}
+}
+
+func TestCache_GetCoalesced(t *testing.T) {
+	var loads atomic.Int32
+	release := make(chan struct{})
+	c := New(&Opts[string, int]{
+		Loader: func(ctx context.Context, k string) (int, error) {
+			loads.Add(1)
+			<-release
+			return len(k), nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.Get(context.Background(), "abc"); err != nil || v != 3 {
+				t.Errorf("Get() = %v, %v, want 3, nil", v, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	if v, ok := c.Load("abc"); !ok || v != 3 {
+		t.Errorf("Load() after Get() = %v, %v, want 3, true", v, ok)
+	}
+}
+
+func TestCache_GetError(t *testing.T) {
+	errDown := errors.New("down")
+	c := New(&Opts[string, int]{
+		Loader: func(ctx context.Context, k string) (int, error) {
+			if k == "panic" {
+				panic("boom")
+			}
+			return 0, errDown
+		},
+	})
+	if _, err := c.Get(context.Background(), "a"); !errors.Is(err, errDown) {
+		t.Errorf("Get() error = %v, want %v", err, errDown)
+	}
+	if _, err := c.Get(context.Background(), "panic"); !errors.Is(err, ErrLoaderPanic) {
+		t.Errorf("Get() error = %v, want ErrLoaderPanic", err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, failed loads must not be cached", c.Len())
+	}
+	if st := c.Stats(); st.Loads != 2 || st.LoadErrors != 2 {
+		t.Errorf("Stats() = %+v", st)
+	}
+
+	if _, err := New[string, int](nil).Get(context.Background(), "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() without loader error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCache_LoadAndDelete(t *testing.T) {
+	c := New[string, int](nil)
+	c.Store("a", 1)
+	if v, ok := c.LoadAndDelete("a"); !ok || v != 1 {
+		t.Errorf("LoadAndDelete() = %v, %v, want 1, true", v, ok)
+	}
+	if _, ok := c.LoadAndDelete("a"); ok {
+		t.Error("LoadAndDelete() on missing key should return false")
+	}
+	c.Store("b", 2)
+	c.Delete("b")
+	c.Store("c", 3)
+	c.Clear()
+	if c.Len() != 0 || c.Cost() != 0 {
+		t.Errorf("Len(), Cost() after Clear() = %d, %d", c.Len(), c.Cost())
+	}
+}
+
+func TestCache_GetCancelled(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+	c := New(&Opts[string, int]{
+		Loader: func(ctx context.Context, k string) (int, error) {
+			once.Do(func() { close(started) })
+			select {
+			case <-release:
+				return 1, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		},
+	})
+
+	first, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		_, err := c.Get(first, "a")
+		errc <- err
+	}()
+	<-started
+	result := make(chan error)
+	go func() {
+		v, err := c.Get(context.Background(), "a")
+		if err == nil && v != 1 {
+			err = fmt.Errorf("Get() = %d, want 1", v)
+		}
+		result <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() of the cancelled caller error = %v", err)
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := c.Get(short, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() with a deadline error = %v, want DeadlineExceeded", err)
+	}
+
+	close(release)
+	if err := <-result; err != nil {
+		t.Errorf("Get() of the remaining caller error = %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+type memItem struct {
+	value    []byte
+	expireAt time.Time
+}
+
+// MemoryBackend is a Backend kept in process memory.
+type MemoryBackend struct {
+	mu    sync.RWMutex
+	items map[string]memItem
+}
+
+// NewMemoryBackend creates a new MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{items: make(map[string]memItem)}
+}
+
+func (b *MemoryBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	b.mu.RLock()
+	it, ok := b.items[key]
+	b.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if expired(it.expireAt) {
+		b.mu.Lock()
+		if cur, ok := b.items[key]; ok && expired(cur.expireAt) {
+			delete(b.items, key)
+		}
+		b.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	return bytes.Clone(it.value), nil
+}
+
+func (b *MemoryBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items[key] = memItem{value: bytes.Clone(value), expireAt: expireAt(ttl)}
+	return nil
+}
+
+func (b *MemoryBackend) Delete(ctx context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.items, key)
+	return nil
+}
@@ -0,0 +1,73 @@
+package pumap
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+// Delta is the result of an incremental fetch.
+type Delta[K comparable, V any] struct {
+	Upserts   map[K]V // keys added or changed since the requested watermark
+	Deletes   []K     // keys removed since the requested watermark
+	Watermark int64   // watermark to pass to the next fetch, e.g. a version or a unix timestamp
+}
+
+// DeltaFunc fetches the changes made after since.
+// A zero since asks for a full load: the returned Upserts then become the whole snapshot
+// and Deletes is ignored.
+type DeltaFunc[K comparable, V any] func(ctx context.Context, since int64) (Delta[K, V], error)
+
+// NewDeltaPUMap creates a PUMap that refreshes incrementally.
+// Each tick the fetcher is called with the watermark of the last successful fetch and
+// its delta is merged into the current snapshot. A full load is done on the first tick,
+// whenever the fetcher returns a zero watermark, and every fullEvery to repair drift;
+// fullEvery <= 0 disables the periodic full load.
+func NewDeltaPUMap[K comparable, V any](interval, fullEvery time.Duration, fetcher DeltaFunc[K, V]) *PUMap[K, V] {
+	m := &PUMap[K, V]{interval: interval, delta: fetcher, fullEvery: fullEvery}
+	m.setup()
+	return m
+}
+
+// refreshDelta calls the delta fetcher once and merges the result into the snapshot.
+func (m *PUMap[K, V]) refreshDelta(ctx context.Context) error {
+	m.mu.Lock()
+	since := m.Status().Watermark
+	if m.fullEvery > 0 && time.Since(m.lastFull) >= m.fullEvery {
+		since = 0
+	}
+	m.mu.Unlock()
+
+	var d Delta[K, V]
+	err := ErrFetchPanic
+	funcs.F(func() { d, err = m.delta(ctx, since) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return m.fail(ctx, err)
+	}
+	now := time.Now()
+	if since == 0 {
+		data := d.Upserts
+		if data == nil {
+			data = make(map[K]V)
+		}
+		m.publish(data)
+		m.lastFull = now
+	} else if len(d.Upserts) > 0 || len(d.Deletes) > 0 {
+		data := maps.Clone(m.load())
+		maps.Copy(data, d.Upserts)
+		for _, k := range d.Deletes {
+			delete(data, k)
+		}
+		m.publish(data)
+	}
+	m.succeed(Status{LastSuccess: now, Watermark: d.Watermark})
+	return nil
+}
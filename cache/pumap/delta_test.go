@@ -0,0 +1,77 @@
+package pumap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeltaPUMap(t *testing.T) {
+	var calls []int64
+	var fail bool
+	m := NewDeltaPUMap(time.Hour, time.Hour, func(ctx context.Context, since int64) (Delta[string, int], error) {
+		calls = append(calls, since)
+		if fail {
+			return Delta[string, int]{}, errors.New("db down")
+		}
+		switch since {
+		case 0:
+			return Delta[string, int]{Upserts: map[string]int{"a": 1, "b": 2}, Watermark: 10}, nil
+		case 10:
+			return Delta[string, int]{Upserts: map[string]int{"a": 3, "c": 4}, Deletes: []string{"b"}, Watermark: 20}, nil
+		default:
+			return Delta[string, int]{Watermark: since}, nil
+		}
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := m.refresh(ctx); err != nil {
+			t.Fatalf("refresh() error = %v", err)
+		}
+	}
+	fail = true
+	if err := m.refresh(ctx); err == nil {
+		t.Fatal("refresh() expected error")
+	}
+
+	want := []int64{0, 10, 20, 20}
+	if len(calls) != len(want) {
+		t.Fatalf("fetcher called with %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("fetcher called with %v, want %v", calls, want)
+		}
+	}
+
+	snap := m.Snapshot()
+	if len(snap) != 2 || snap["a"] != 3 || snap["c"] != 4 {
+		t.Errorf("Snapshot() = %v, want map[a:3 c:4]", snap)
+	}
+	if st := m.Status(); st.Watermark != 20 || st.Failures != 1 {
+		t.Errorf("Status() = %+v, want watermark 20 and 1 failure", st)
+	}
+}
+
+func TestDeltaPUMapFullReload(t *testing.T) {
+	var fulls int
+	m := NewDeltaPUMap(time.Hour, time.Nanosecond, func(ctx context.Context, since int64) (Delta[string, int], error) {
+		if since == 0 {
+			fulls++
+		}
+		return Delta[string, int]{Upserts: map[string]int{"a": 1}, Watermark: 1}, nil
+	})
+	m.Put("stale", 0)
+	for i := 0; i < 2; i++ {
+		time.Sleep(time.Millisecond)
+		m.refresh(context.Background())
+	}
+	if fulls != 2 {
+		t.Errorf("full reloads = %d, want 2", fulls)
+	}
+	if _, ok := m.Get("stale"); ok {
+		t.Error("full reload did not drop stale key")
+	}
+}
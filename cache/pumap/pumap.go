@@ -1,65 +1,291 @@
 package pumap
 
 import (
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/chenyan/wheels/cc"
 	"github.com/chenyan/wheels/funcs"
 )
 
+// ErrFetchPanic is recorded as the last error when the fetcher panics.
+var ErrFetchPanic = errors.New("pumap: fetcher panicked")
+
+// FetchFunc loads a full snapshot of the map.
+// A non-nil error means the snapshot is discarded and the last good one is kept.
+type FetchFunc[K comparable, V any] func(ctx context.Context) (map[K]V, error)
+
+// Status reports the health of the refresh loop.
+type Status struct {
+	LastError   error     // error of the last failed fetch, nil after a success
+	LastSuccess time.Time // time of the last successful fetch
+	Failures    int       // consecutive failures since the last success
+	Watermark   int64     // watermark of the last successful fetch in delta mode
+}
+
 // PUMap is a concurrent map with a periodic update mechanism.
-// It is implemented as a struct with a map, a read-write mutex, a fetcher function, and an interval.
+// The current content is an immutable snapshot published through an atomic pointer,
+// so readers never wait on a refresh: the fetcher runs outside any lock and
+// its result replaces the snapshot in a single atomic swap.
 // The map keys must be comparable and the values can be of any type.
 // The fetcher function is used to update the map periodically.
-// The running field is an atomic boolean that indicates whether the update mechanism is running or not.
+// If the fetcher fails, the last good snapshot is kept and the failure is reported by Status.
+// Put and Remove copy the snapshot, so they are O(n) and meant for occasional overrides.
+// Start launches the refresh loop, Stop waits for it to exit.
 type PUMap[K comparable, V any] struct {
-	sync.RWMutex
-	m        map[K]V
+	mu       sync.Mutex // serializes writers, never held by readers
+	snap     atomic.Pointer[map[K]V]
 	interval time.Duration
-	fetcher  func() map[K]V
-	running  atomic.Bool
+	fetcher  FetchFunc[K, V]
+	status   atomic.Pointer[Status]
+	opts     Opts
+	flight   cc.Group[struct{}, struct{}]   // coalesces concurrent refreshes
+	subs     map[*subscriber[K, V]]struct{} // guarded by mu
+
+	ready     chan struct{} // closed after the first successful fetch
+	readyOnce sync.Once
+
+	life   sync.Mutex // guards cancel and done
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the refresh loop exits
+
+	// delta mode, see NewDeltaPUMap
+	delta     DeltaFunc[K, V]
+	fullEvery time.Duration
+	lastFull  time.Time
+}
+
+// Opts tunes the refresh loop of a PUMap.
+type Opts struct {
+	// Jitter spreads each wait uniformly over interval*(1±Jitter) so that replicas
+	// do not hit the upstream at the same moment. It is clamped to [0, 1].
+	Jitter float64
+	// Backoff is the wait after a failed fetch, doubled for each consecutive failure.
+	// Zero means failures are retried after the normal interval.
+	Backoff time.Duration
+	// MaxBackoff caps the wait after failures, it defaults to the interval.
+	MaxBackoff time.Duration
 }
 
 // NewPUMap creates a new PUMap
 func NewPUMap[K comparable, V any](interval time.Duration, fetcher func() map[K]V) *PUMap[K, V] {
-	return &PUMap[K, V]{m: make(map[K]V), interval: interval, fetcher: fetcher}
+	return NewPUMapCtx(interval, func(context.Context) (map[K]V, error) {
+		return fetcher(), nil
+	})
+}
+
+// NewPUMapCtx creates a new PUMap with a context-aware fetcher that can report errors.
+func NewPUMapCtx[K comparable, V any](interval time.Duration, fetcher FetchFunc[K, V]) *PUMap[K, V] {
+	m := &PUMap[K, V]{interval: interval, fetcher: fetcher}
+	m.setup()
+	return m
+}
+
+func (m *PUMap[K, V]) setup() {
+	m.publish(make(map[K]V))
+	m.status.Store(&Status{})
+	m.ready = make(chan struct{})
+}
+
+// WithOpts sets the refresh options. It must be called before Start.
+func (m *PUMap[K, V]) WithOpts(opts Opts) *PUMap[K, V] {
+	m.opts = opts
+	return m
+}
+
+// publish makes data the current snapshot and notifies the subscribers.
+// data must not be modified afterwards. It must be called with m.mu held,
+// except from setup.
+func (m *PUMap[K, V]) publish(data map[K]V) {
+	old := m.snap.Swap(&data)
+	if old == nil {
+		return
+	}
+	for s := range m.subs {
+		s.push(*old, data)
+	}
+}
+
+func (m *PUMap[K, V]) load() map[K]V {
+	return *m.snap.Load()
 }
 
 func (m *PUMap[K, V]) Put(k K, v V) {
-	m.Lock()
-	defer m.Unlock()
-	m.m[k] = v
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data := maps.Clone(m.load())
+	data[k] = v
+	m.publish(data)
 }
 
 func (m *PUMap[K, V]) Get(k K) (V, bool) {
-	m.RLock()
-	defer m.RUnlock()
-	v, ok := m.m[k]
+	v, ok := m.load()[k]
 	return v, ok
 }
 
 func (m *PUMap[K, V]) Remove(k K) {
-	m.Lock()
-	defer m.Unlock()
-	delete(m.m, k)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old := m.load()
+	if _, ok := old[k]; !ok {
+		return
+	}
+	data := maps.Clone(old)
+	delete(data, k)
+	m.publish(data)
 }
 
-func (m *PUMap[K, V]) Start() {
-	if m.running.Load() {
+// Len returns the number of entries in the current snapshot.
+func (m *PUMap[K, V]) Len() int {
+	return len(m.load())
+}
+
+// Snapshot returns the current snapshot. It must be treated as read-only.
+func (m *PUMap[K, V]) Snapshot() map[K]V {
+	return m.load()
+}
+
+// Status returns the health of the refresh loop.
+func (m *PUMap[K, V]) Status() Status {
+	return *m.status.Load()
+}
+
+// refresh calls the fetcher once and replaces the snapshot on success.
+// The fetcher runs without holding any lock.
+func (m *PUMap[K, V]) refresh(ctx context.Context) error {
+	if m.delta != nil {
+		return m.refreshDelta(ctx)
+	}
+	var data map[K]V
+	err := ErrFetchPanic
+	funcs.F(func() { data, err = m.fetcher(ctx) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		// a fetch every caller gave up on must not publish after a newer one
+		err = ctx.Err()
+	}
+	if err != nil {
+		return m.fail(ctx, err)
+	}
+	if data == nil {
+		data = make(map[K]V)
+	}
+	m.publish(data)
+	m.succeed(Status{LastSuccess: time.Now()})
+	return nil
+}
+
+// succeed records a successful fetch. It must be called with m.mu held.
+func (m *PUMap[K, V]) succeed(st Status) {
+	m.status.Store(&st)
+	m.readyOnce.Do(func() { close(m.ready) })
+}
+
+// fail records a failed fetch, unless it failed because ctx was cancelled, e.g. by Stop,
+// which says nothing about the upstream. It must be called with m.mu held.
+func (m *PUMap[K, V]) fail(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	st := m.Status()
+	st.LastError = err
+	st.Failures++
+	m.status.Store(&st)
+	return err
+}
+
+// Refresh fetches immediately and waits for the result.
+// On failure the current snapshot is kept and the error is returned.
+// Concurrent calls, including the ones of the refresh loop, share a single fetch,
+// so snapshots are never published out of order. The fetch runs on a context detached
+// from the deadline of any single caller, see cc.Group.DoContext: each caller returns
+// ctx.Err() when its own ctx is done, and the fetch is cancelled and discarded once
+// every caller has given up.
+func (m *PUMap[K, V]) Refresh(ctx context.Context) error {
+	_, err, _ := m.flight.DoContext(ctx, struct{}{}, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, m.refresh(ctx)
+	})
+	return err
+}
+
+// WaitReady blocks until the first successful fetch or until ctx is done.
+func (m *PUMap[K, V]) WaitReady(ctx context.Context) error {
+	select {
+	case <-m.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start launches the refresh loop, which fetches right away and then every interval
+// until ctx is done or Stop is called. Calling Start on a running PUMap is a no-op.
+func (m *PUMap[K, V]) Start(ctx context.Context) {
+	m.life.Lock()
+	defer m.life.Unlock()
+	if m.done != nil {
 		return
 	}
-	m.running.Store(true)
-	go func() {
-		for m.running.Load() {
-			m.Lock()
-			funcs.F(func() { m.m = m.fetcher() })
-			m.Unlock()
-			time.Sleep(m.interval)
-		}
-	}()
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go m.loop(ctx, m.done)
 }
 
+// Stop stops the refresh loop and waits until it has exited, so no fetch will start
+// from the loop once Stop returns. The fetch of the loop is cancelled, unless a Refresh
+// call is waiting for it too.
 func (m *PUMap[K, V]) Stop() {
-	m.running.Store(false)
+	m.life.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.life.Unlock()
+	if done == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (m *PUMap[K, V]) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for {
+		err := m.Refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		timer := time.NewTimer(m.nextWait(err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// nextWait computes the delay before the next fetch, given the result of the last one.
+func (m *PUMap[K, V]) nextWait(err error) time.Duration {
+	wait := m.interval
+	if err != nil && m.opts.Backoff > 0 {
+		limit := m.opts.MaxBackoff
+		if limit <= 0 {
+			limit = m.interval
+		}
+		wait = m.opts.Backoff
+		for i := 1; i < m.Status().Failures && wait < limit; i++ {
+			wait *= 2
+		}
+		wait = min(wait, limit)
+	}
+	if j := min(max(m.opts.Jitter, 0), 1); j > 0 {
+		wait = time.Duration(float64(wait) * (1 + j*(2*rand.Float64()-1)))
+	}
+	return wait
 }
@@ -0,0 +1,93 @@
+package pumap
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	benchKeys      = 1024
+	benchFetchTime = time.Millisecond
+)
+
+func benchFetch() map[int]int {
+	time.Sleep(benchFetchTime)
+	m := make(map[int]int, benchKeys)
+	for i := 0; i < benchKeys; i++ {
+		m[i] = i
+	}
+	return m
+}
+
+// lockedMap mirrors the previous PUMap design, which held the write lock
+// for the whole duration of the fetch.
+type lockedMap struct {
+	sync.RWMutex
+	m map[int]int
+}
+
+func (m *lockedMap) Get(k int) (int, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *lockedMap) refresh() {
+	m.Lock()
+	defer m.Unlock()
+	m.m = benchFetch()
+}
+
+// refreshLoop keeps refreshing, pausing as long as a fetch takes between
+// two refreshes, until the benchmark is done.
+func refreshLoop(refresh func()) func() {
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for !stop.Load() {
+			refresh()
+			time.Sleep(benchFetchTime)
+		}
+	}()
+	return func() {
+		stop.Store(true)
+		wg.Wait()
+	}
+}
+
+func BenchmarkGetDuringRefresh_Locked(b *testing.B) {
+	m := &lockedMap{m: benchFetch()}
+	stop := refreshLoop(m.refresh)
+	defer stop()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Get(i % benchKeys)
+			i++
+		}
+	})
+}
+
+func BenchmarkGetDuringRefresh_PUMap(b *testing.B) {
+	m := NewPUMap(time.Hour, benchFetch)
+	m.refresh(context.Background())
+	stop := refreshLoop(func() { m.refresh(context.Background()) })
+	defer stop()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Get(i % benchKeys)
+			i++
+		}
+	})
+}
@@ -2,7 +2,10 @@
 package pumap
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -15,10 +18,14 @@ func TestPUMap(t *testing.T) {
 			3: "three",
 		}
 	})
-	m.Start()
+	m.Start(context.Background())
 	defer m.Stop()
 
-	time.Sleep(2 * time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.WaitReady(ctx); err != nil {
+		t.Fatalf("WaitReady() error = %v", err)
+	}
 
 	if v, ok := m.Get(1); !ok || v != "one" {
 		t.Errorf("expected value 'one' for key 1, got %v", v)
@@ -40,3 +47,197 @@ func TestPUMap(t *testing.T) {
 }
 
 // END: 7c8c5d8d7b5c
+
+func TestPUMapCtxKeepLastGood(t *testing.T) {
+	fail := false
+	m := NewPUMapCtx(time.Hour, func(ctx context.Context) (map[string]int, error) {
+		if fail {
+			return nil, errors.New("db down")
+		}
+		return map[string]int{"a": 1}, nil
+	})
+
+	if err := m.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	st := m.Status()
+	if st.LastError != nil || st.Failures != 0 || st.LastSuccess.IsZero() {
+		t.Errorf("Status() after success = %+v", st)
+	}
+
+	fail = true
+	for i := 0; i < 2; i++ {
+		if err := m.refresh(context.Background()); err == nil {
+			t.Fatal("refresh() expected error")
+		}
+	}
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v, want last good value 1", v, ok)
+	}
+	st = m.Status()
+	if st.LastError == nil || st.Failures != 2 {
+		t.Errorf("Status() after failures = %+v, want 2 failures", st)
+	}
+}
+
+func TestPUMapCtxPanic(t *testing.T) {
+	m := NewPUMapCtx(time.Hour, func(ctx context.Context) (map[string]int, error) {
+		panic("boom")
+	})
+	m.Put("a", 1)
+	if err := m.refresh(context.Background()); !errors.Is(err, ErrFetchPanic) {
+		t.Errorf("refresh() error = %v, want ErrFetchPanic", err)
+	}
+	if _, ok := m.Get("a"); !ok {
+		t.Error("Get(a) lost value after panic")
+	}
+}
+
+func TestPUMapLifecycle(t *testing.T) {
+	var fetches atomic.Int32
+	m := NewPUMapCtx(time.Millisecond, func(ctx context.Context) (map[string]int, error) {
+		n := fetches.Add(1)
+		return map[string]int{"n": int(n)}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m.Start(ctx)
+	m.Start(ctx)
+	if err := m.WaitReady(ctx); err != nil {
+		t.Fatalf("WaitReady() error = %v", err)
+	}
+	m.Stop()
+	stopped := fetches.Load()
+	time.Sleep(10 * time.Millisecond)
+	if n := fetches.Load(); n != stopped {
+		t.Errorf("fetches after Stop() = %d, want %d", n, stopped)
+	}
+	m.Stop()
+
+	if err := m.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if v, _ := m.Get("n"); v != int(stopped)+1 {
+		t.Errorf("Get(n) after Refresh() = %d, want %d", v, stopped+1)
+	}
+}
+
+func TestPUMapWaitReadyTimeout(t *testing.T) {
+	m := NewPUMapCtx(time.Hour, func(ctx context.Context) (map[string]int, error) {
+		return nil, errors.New("db down")
+	})
+	m.Refresh(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.WaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitReady() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestPUMapNextWait(t *testing.T) {
+	m := NewPUMapCtx(time.Minute, func(ctx context.Context) (map[string]int, error) {
+		return nil, errors.New("db down")
+	}).WithOpts(Opts{Backoff: time.Second, MaxBackoff: 3 * time.Second})
+
+	if w := m.nextWait(nil); w != time.Minute {
+		t.Errorf("nextWait(nil) = %v, want %v", w, time.Minute)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	for i, w := range want {
+		err := m.Refresh(context.Background())
+		if got := m.nextWait(err); got != w {
+			t.Errorf("nextWait() after %d failures = %v, want %v", i+1, got, w)
+		}
+	}
+
+	m.WithOpts(Opts{Jitter: 0.5})
+	for i := 0; i < 100; i++ {
+		if w := m.nextWait(nil); w < 30*time.Second || w > 90*time.Second {
+			t.Fatalf("nextWait() with jitter = %v, want within [30s, 90s]", w)
+		}
+	}
+}
+
+func TestPUMapRefreshDuringLoop(t *testing.T) {
+	var running, overlaps atomic.Int32
+	m := NewPUMapCtx(time.Millisecond, func(ctx context.Context) (map[string]int, error) {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer running.Add(-1)
+		time.Sleep(100 * time.Microsecond)
+		return map[string]int{}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m.Start(ctx)
+	for i := 0; i < 50; i++ {
+		if err := m.Refresh(ctx); err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+	}
+	m.Stop()
+	if n := overlaps.Load(); n > 0 {
+		t.Errorf("%d fetches overlapped", n)
+	}
+}
+
+func TestPUMapStopMidFetch(t *testing.T) {
+	started := make(chan struct{}, 1)
+	m := NewPUMapCtx(time.Hour, func(ctx context.Context) (map[string]int, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.Start(context.Background())
+	<-started
+	m.Stop()
+	if st := m.Status(); st.Failures != 0 || st.LastError != nil {
+		t.Errorf("Status() after Stop() = %+v, want no failure", st)
+	}
+}
+
+func TestPUMapRefreshOwnContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	m := NewPUMapCtx(time.Hour, func(ctx context.Context) (map[string]int, error) {
+		started <- struct{}{}
+		select {
+		case <-release:
+			return map[string]int{"a": 1}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	m.Start(context.Background())
+	<-started
+
+	short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Refresh(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Refresh() joining the loop fetch error = %v, want DeadlineExceeded", err)
+	}
+
+	refreshed := make(chan error)
+	go func() { refreshed <- m.Refresh(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop() blocked on a fetch shared with Refresh()")
+	}
+
+	close(release)
+	if err := <-refreshed; err != nil {
+		t.Errorf("Refresh() after Stop() of the loop error = %v", err)
+	}
+	if v, _ := m.Get("a"); v != 1 {
+		t.Errorf("Get(a) = %d, want 1", v)
+	}
+}
@@ -0,0 +1,133 @@
+package pumap
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+// Diff describes how the content of a PUMap changed.
+type Diff[K comparable, V any] struct {
+	Added   map[K]V // keys that are new, with their values
+	Removed map[K]V // keys that are gone, with their last values
+	Changed map[K]V // keys whose value changed, with their new values
+	Old     map[K]V // snapshot before the change, read-only
+	New     map[K]V // snapshot after the change, read-only
+}
+
+// IsEmpty reports whether the diff contains no change.
+func (d Diff[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// NewDiff compares two snapshots. A nil eq compares values with reflect.DeepEqual.
+func NewDiff[K comparable, V any](old, new map[K]V, eq func(a, b V) bool) Diff[K, V] {
+	if eq == nil {
+		eq = func(a, b V) bool { return reflect.DeepEqual(a, b) }
+	}
+	d := Diff[K, V]{Added: map[K]V{}, Removed: map[K]V{}, Changed: map[K]V{}, Old: old, New: new}
+	for k, v := range new {
+		if ov, ok := old[k]; !ok {
+			d.Added[k] = v
+		} else if !eq(ov, v) {
+			d.Changed[k] = v
+		}
+	}
+	for k, v := range old {
+		if _, ok := new[k]; !ok {
+			d.Removed[k] = v
+		}
+	}
+	return d
+}
+
+// subscriber coalesces snapshot swaps until its goroutine gets to them,
+// so a slow subscriber never blocks the writers and never misses a change.
+type subscriber[K comparable, V any] struct {
+	mu      sync.Mutex
+	old     map[K]V
+	new     map[K]V
+	pending bool
+
+	eq     func(a, b V) bool
+	notify chan struct{} // capacity 1, signals pending changes
+	quit   chan struct{}
+}
+
+func (s *subscriber[K, V]) push(old, new map[K]V) {
+	s.mu.Lock()
+	if !s.pending {
+		s.old, s.pending = old, true
+	}
+	s.new = new
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber[K, V]) run(deliver func(Diff[K, V])) {
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-s.notify:
+		}
+		s.mu.Lock()
+		old, new := s.old, s.new
+		s.old, s.new, s.pending = nil, nil, false
+		s.mu.Unlock()
+		if d := NewDiff(old, new, s.eq); !d.IsEmpty() {
+			funcs.F(func() { deliver(d) })
+		}
+	}
+}
+
+// Subscribe calls fn with the diff after each change of the content, including Put and Remove.
+// fn runs on a dedicated goroutine; changes that happen while fn is busy are merged
+// into the next diff. eq compares values, nil means reflect.DeepEqual.
+// The returned function cancels the subscription.
+func (m *PUMap[K, V]) Subscribe(eq func(a, b V) bool, fn func(Diff[K, V])) (cancel func()) {
+	s := m.addSubscriber(eq)
+	go s.run(fn)
+	return func() { m.removeSubscriber(s) }
+}
+
+// SubscribeChan is like Subscribe but delivers the diffs on a channel with the given buffer.
+// The channel is closed once the subscription is cancelled.
+func (m *PUMap[K, V]) SubscribeChan(eq func(a, b V) bool, buffer int) (<-chan Diff[K, V], func()) {
+	s := m.addSubscriber(eq)
+	ch := make(chan Diff[K, V], buffer)
+	go func() {
+		defer close(ch)
+		s.run(func(d Diff[K, V]) {
+			select {
+			case ch <- d:
+			case <-s.quit:
+			}
+		})
+	}()
+	return ch, func() { m.removeSubscriber(s) }
+}
+
+func (m *PUMap[K, V]) addSubscriber(eq func(a, b V) bool) *subscriber[K, V] {
+	s := &subscriber[K, V]{eq: eq, notify: make(chan struct{}, 1), quit: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subs == nil {
+		m.subs = make(map[*subscriber[K, V]]struct{})
+	}
+	m.subs[s] = struct{}{}
+	return s
+}
+
+func (m *PUMap[K, V]) removeSubscriber(s *subscriber[K, V]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subs[s]; ok {
+		delete(m.subs, s)
+		close(s.quit)
+	}
+}
@@ -0,0 +1,103 @@
+package pumap
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewDiff(t *testing.T) {
+	old := map[string]int{"a": 1, "b": 2, "c": 3}
+	new := map[string]int{"a": 1, "b": 20, "d": 4}
+	d := NewDiff(old, new, nil)
+	if len(d.Added) != 1 || d.Added["d"] != 4 {
+		t.Errorf("Added = %v, want map[d:4]", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed["c"] != 3 {
+		t.Errorf("Removed = %v, want map[c:3]", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed["b"] != 20 {
+		t.Errorf("Changed = %v, want map[b:20]", d.Changed)
+	}
+	if !NewDiff(old, old, nil).IsEmpty() {
+		t.Error("NewDiff(old, old) is not empty")
+	}
+}
+
+func TestPUMapSubscribeChan(t *testing.T) {
+	data := map[string]int{"a": 1}
+	m := NewPUMapCtx(time.Hour, func(ctx context.Context) (map[string]int, error) {
+		return data, nil
+	})
+	ch, cancel := m.SubscribeChan(func(a, b int) bool { return a == b }, 1)
+
+	m.Refresh(context.Background())
+	select {
+	case d := <-ch:
+		if d.Added["a"] != 1 {
+			t.Errorf("first diff = %+v, want a added", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no diff after first refresh")
+	}
+
+	// an identical snapshot is not delivered
+	data = map[string]int{"a": 1}
+	m.Refresh(context.Background())
+	m.Put("b", 2)
+	select {
+	case d := <-ch:
+		if len(d.Added) != 1 || d.Added["b"] != 2 || len(d.Changed) != 0 {
+			t.Errorf("second diff = %+v, want only b added", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no diff after Put")
+	}
+
+	cancel()
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("unexpected diff after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestPUMapSubscribeSlow(t *testing.T) {
+	m := NewPUMap(time.Hour, func() map[int]int { return nil })
+	release := make(chan struct{})
+	got := make(chan Diff[int, int], 10)
+	cancel := m.Subscribe(nil, func(d Diff[int, int]) {
+		<-release
+		got <- d
+	})
+	defer cancel()
+
+	// the writers must not wait on the blocked subscriber
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			m.Put(i, i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Put blocked on a slow subscriber")
+	}
+
+	close(release)
+	added := 0
+	for added < 100 {
+		select {
+		case d := <-got:
+			added += len(d.Added)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d added keys, want 100", added)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chenyan/wheels/cache/lru"
+)
+
+// TieredOpts configures a Tiered cache.
+type TieredOpts struct {
+	LocalSize int           // maximum entries of the local LRU, <= 0 means unlimited
+	LocalTTL  time.Duration // ttl of local entries, defaults to TTL
+	TTL       time.Duration // ttl of backend entries, 0 means forever
+	Codec     Codec         // encoding of backend values, defaults to JSONCodec
+}
+
+// Tiered is a two-tier cache: reads are served from a local LRU and fall back
+// to the Backend, whose values are decoded and kept in the local tier.
+// Writes go to both tiers.
+type Tiered[V any] struct {
+	local   *lru.Cache[string, V]
+	backend Backend
+	opts    TieredOpts
+}
+
+// NewTiered creates a Tiered cache on top of backend. opts may be nil.
+func NewTiered[V any](backend Backend, opts *TieredOpts) *Tiered[V] {
+	t := &Tiered[V]{backend: backend}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.Codec == nil {
+		t.opts.Codec = JSONCodec
+	}
+	if t.opts.LocalTTL <= 0 {
+		t.opts.LocalTTL = t.opts.TTL
+	}
+	t.local = lru.New(&lru.Opts[string, V]{MaxEntries: t.opts.LocalSize, TTL: t.opts.LocalTTL})
+	return t
+}
+
+// Get returns the value for key, or ErrNotFound.
+func (t *Tiered[V]) Get(ctx context.Context, key string) (V, error) {
+	var v V
+	if v, ok := t.local.Load(key); ok {
+		return v, nil
+	}
+	bs, err := t.backend.Get(ctx, key)
+	if err != nil {
+		return v, err
+	}
+	if err := t.opts.Codec.Unmarshal(bs, &v); err != nil {
+		return v, fmt.Errorf("failed to decode cache value: %s %w", key, err)
+	}
+	t.local.Store(key, v)
+	return v, nil
+}
+
+// Set stores the value in the backend, then in the local tier.
+func (t *Tiered[V]) Set(ctx context.Context, key string, value V) error {
+	bs, err := t.opts.Codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache value: %s %w", key, err)
+	}
+	if err := t.backend.Set(ctx, key, bs, t.opts.TTL); err != nil {
+		return err
+	}
+	t.local.Store(key, value)
+	return nil
+}
+
+// Delete removes key from both tiers.
+func (t *Tiered[V]) Delete(ctx context.Context, key string) error {
+	t.local.Delete(key)
+	return t.backend.Delete(ctx, key)
+}
+
+// Local returns the local tier, e.g. to read its Stats.
+func (t *Tiered[V]) Local() *lru.Cache[string, V] {
+	return t.local
+}
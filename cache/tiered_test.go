@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	c := NewTiered[user](backend, &TieredOpts{LocalSize: 1})
+
+	if err := c.Set(ctx, "u1", user{1, "alice"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if bs, _ := backend.Get(ctx, "u1"); string(bs) != `{"id":1,"name":"alice"}` {
+		t.Errorf("backend value = %s", bs)
+	}
+	c.Set(ctx, "u2", user{2, "bob"}) // evicts u1 from the local tier
+
+	if u, err := c.Get(ctx, "u1"); err != nil || u.Name != "alice" {
+		t.Errorf("Get(u1) = %+v, %v, want alice from the backend", u, err)
+	}
+	if _, ok := c.Local().Load("u1"); !ok {
+		t.Error("Get(u1) did not warm the local tier")
+	}
+
+	// a new cache on the same backend starts warm
+	c2 := NewTiered[user](backend, nil)
+	if u, err := c2.Get(ctx, "u2"); err != nil || u.Name != "bob" {
+		t.Errorf("Get(u2) = %+v, %v, want bob", u, err)
+	}
+
+	c.Delete(ctx, "u1")
+	if _, err := c.Get(ctx, "u1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(u1) after Delete() error = %v, want ErrNotFound", err)
+	}
+}
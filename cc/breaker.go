@@ -0,0 +1,202 @@
+package cc
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by a Breaker that rejects calls.
+var ErrOpen = errors.New("cc: circuit breaker is open")
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	StateClosed   State = iota // calls pass, outcomes are counted
+	StateOpen                  // calls are rejected until the cool-down elapses
+	StateHalfOpen              // a few trial calls decide between closed and open
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerOpts configures a Breaker. Zero fields take the documented defaults.
+type BreakerOpts struct {
+	FailureRatio  float64       // failure ratio that opens the breaker, default 0.5
+	MinRequests   int           // completed calls needed in a window before the ratio is checked, default 10
+	Window        time.Duration // counts are reset every window while closed, default 10s
+	CoolDown      time.Duration // time spent open before trying again, default 5s
+	HalfOpenCalls int           // trial calls allowed while half-open, default 1
+	// OnStateChange is called with the breaker locked and must not call back into it.
+	OnStateChange func(from, to State)
+}
+
+// Counts are the outcomes recorded in the current window.
+type Counts struct {
+	Requests  int
+	Successes int
+	Failures  int
+}
+
+// Breaker is a circuit breaker. While closed it counts outcomes, and opens once
+// the failure ratio is reached. After the cool-down it lets a few trial calls
+// through: one failure opens it again, all successes close it.
+type Breaker struct {
+	mu       sync.Mutex
+	opts     BreakerOpts
+	state    State
+	counts   Counts
+	deadline time.Time // end of the window when closed, of the cool-down when open
+	inflight int       // trial calls in flight when half-open
+	gen      uint64    // bumped on every state change or window reset
+}
+
+// NewBreaker creates a closed Breaker. opts may be nil.
+func NewBreaker(opts *BreakerOpts) *Breaker {
+	b := &Breaker{}
+	if opts != nil {
+		b.opts = *opts
+	}
+	if b.opts.FailureRatio <= 0 {
+		b.opts.FailureRatio = 0.5
+	}
+	if b.opts.MinRequests <= 0 {
+		b.opts.MinRequests = 10
+	}
+	if b.opts.Window <= 0 {
+		b.opts.Window = 10 * time.Second
+	}
+	if b.opts.CoolDown <= 0 {
+		b.opts.CoolDown = 5 * time.Second
+	}
+	if b.opts.HalfOpenCalls <= 0 {
+		b.opts.HalfOpenCalls = 1
+	}
+	b.deadline = time.Now().Add(b.opts.Window)
+	return b
+}
+
+// State returns the current state.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tick(time.Now())
+	return b.state
+}
+
+// Counts returns the outcomes recorded in the current window.
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tick(time.Now())
+	return b.counts
+}
+
+// Allow reports whether a call may proceed. On success the caller must report
+// the outcome of the call through done exactly once.
+func (b *Breaker) Allow() (done func(success bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tick(time.Now())
+	switch b.state {
+	case StateOpen:
+		return nil, ErrOpen
+	case StateHalfOpen:
+		if b.inflight >= b.opts.HalfOpenCalls {
+			return nil, ErrOpen
+		}
+		b.inflight++
+	}
+	b.counts.Requests++
+	gen := b.gen
+	var once sync.Once
+	return func(success bool) {
+		once.Do(func() { b.report(gen, success) })
+	}, nil
+}
+
+// Do runs fn if the breaker allows it and records its error as the outcome.
+func (b *Breaker) Do(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err == nil)
+	return err
+}
+
+func (b *Breaker) report(gen uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tick(now)
+	if b.gen != gen {
+		// outcome of a call started in a previous state or window
+		return
+	}
+	if success {
+		b.counts.Successes++
+	} else {
+		b.counts.Failures++
+	}
+	switch b.state {
+	case StateClosed:
+		completed := b.counts.Successes + b.counts.Failures
+		if completed >= b.opts.MinRequests &&
+			float64(b.counts.Failures) >= b.opts.FailureRatio*float64(completed) {
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.inflight--
+		if !success {
+			b.setState(StateOpen, now)
+		} else if b.counts.Successes >= b.opts.HalfOpenCalls {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+// tick applies the time based transitions. It must be called with b.mu held.
+func (b *Breaker) tick(now time.Time) {
+	if now.Before(b.deadline) {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		b.counts = Counts{}
+		b.deadline = now.Add(b.opts.Window)
+		b.gen++
+	case StateOpen:
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+func (b *Breaker) setState(to State, now time.Time) {
+	from := b.state
+	b.state = to
+	b.counts = Counts{}
+	b.inflight = 0
+	b.gen++
+	switch to {
+	case StateClosed:
+		b.deadline = now.Add(b.opts.Window)
+	case StateOpen:
+		b.deadline = now.Add(b.opts.CoolDown)
+	case StateHalfOpen:
+		b.deadline = time.Time{}
+	}
+	if b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
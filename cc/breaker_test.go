@@ -0,0 +1,83 @@
+package cc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	var changes []string
+	b := NewBreaker(&BreakerOpts{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		CoolDown:     20 * time.Millisecond,
+		OnStateChange: func(from, to State) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+	errFail := errors.New("fail")
+	ok := func() error { return nil }
+	fail := func() error { return errFail }
+
+	b.Do(ok)
+	b.Do(fail)
+	b.Do(ok)
+	if b.State() != StateClosed {
+		t.Fatalf("State() = %v before MinRequests, want closed", b.State())
+	}
+	b.Do(fail)
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %v at 50%% failures, want open", b.State())
+	}
+	if err := b.Do(ok); !errors.Is(err, ErrOpen) {
+		t.Errorf("Do() while open error = %v, want ErrOpen", err)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("State() = %v after cool-down, want half-open", b.State())
+	}
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() trial error = %v", err)
+	}
+	if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("second Allow() while half-open error = %v, want ErrOpen", err)
+	}
+	done(false)
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %v after failed trial, want open", b.State())
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if err := b.Do(ok); err != nil {
+		t.Fatalf("Do() trial error = %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Errorf("State() = %v after successful trial, want closed", b.State())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("state changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("state changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestBreaker_Window(t *testing.T) {
+	b := NewBreaker(&BreakerOpts{MinRequests: 2, Window: 10 * time.Millisecond})
+	b.Do(func() error { return errors.New("fail") })
+	time.Sleep(15 * time.Millisecond)
+	if c := b.Counts(); c.Requests != 0 {
+		t.Errorf("Counts() after window = %+v, want reset", c)
+	}
+	b.Do(func() error { return errors.New("fail") })
+	if b.State() != StateClosed {
+		t.Error("failures of different windows should not add up")
+	}
+}
@@ -0,0 +1,59 @@
+package cc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+// ErrGroup runs functions in goroutines with an optional concurrency limit,
+// and collects the first error. Panics are recovered and reported as *funcs.PanicError.
+type ErrGroup struct {
+	cancel context.CancelFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// NewErrGroup creates an ErrGroup running at most limit functions at once,
+// limit <= 0 means unlimited. The returned context is cancelled by the first
+// error or when Wait returns.
+func NewErrGroup(ctx context.Context, limit int) (*ErrGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &ErrGroup{cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+// Go runs f in a new goroutine, blocking while the limit is reached.
+func (g *ErrGroup) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+			g.wg.Done()
+		}()
+		if err := funcs.E(f); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait waits for all functions and returns the first error.
+func (g *ErrGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,84 @@
+package cc
+
+import "sync"
+
+type keyedEntry struct {
+	mu   sync.Mutex
+	refs int // holders and waiters, guarded by KeyedMutex.mu
+}
+
+// KeyedMutex serializes work per key without a global lock.
+// The lock of a key is dropped as soon as nobody holds or waits for it,
+// so the memory used is proportional to the number of keys in use.
+// The zero value is ready to use.
+type KeyedMutex[K comparable] struct {
+	mu      sync.Mutex
+	entries map[K]*keyedEntry
+}
+
+// NewKeyedMutex creates a new KeyedMutex.
+func NewKeyedMutex[K comparable]() *KeyedMutex[K] {
+	return &KeyedMutex[K]{}
+}
+
+// Lock locks key, blocking while another goroutine holds it.
+func (km *KeyedMutex[K]) Lock(key K) {
+	km.mu.Lock()
+	if km.entries == nil {
+		km.entries = make(map[K]*keyedEntry)
+	}
+	e, ok := km.entries[key]
+	if !ok {
+		e = &keyedEntry{}
+		km.entries[key] = e
+	}
+	e.refs++
+	km.mu.Unlock()
+	e.mu.Lock()
+}
+
+// TryLock locks key if it is free and reports whether it did.
+func (km *KeyedMutex[K]) TryLock(key K) bool {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if _, ok := km.entries[key]; ok {
+		return false
+	}
+	if km.entries == nil {
+		km.entries = make(map[K]*keyedEntry)
+	}
+	e := &keyedEntry{refs: 1}
+	e.mu.Lock()
+	km.entries[key] = e
+	return true
+}
+
+// Unlock unlocks key. It panics if key is not locked.
+func (km *KeyedMutex[K]) Unlock(key K) {
+	km.mu.Lock()
+	e, ok := km.entries[key]
+	if !ok {
+		km.mu.Unlock()
+		panic("cc: unlock of unlocked key")
+	}
+	e.refs--
+	if e.refs == 0 {
+		delete(km.entries, key)
+	}
+	km.mu.Unlock()
+	e.mu.Unlock()
+}
+
+// Do runs f while holding the lock of key.
+func (km *KeyedMutex[K]) Do(key K, f func()) {
+	km.Lock(key)
+	defer km.Unlock(key)
+	f()
+}
+
+// Len returns the number of keys currently held or waited for.
+func (km *KeyedMutex[K]) Len() int {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	return len(km.entries)
+}
@@ -0,0 +1,65 @@
+package cc
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var km KeyedMutex[string]
+	counts := map[string]int{}
+	var mu sync.Mutex // guards the counts map itself, not the values
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for _, key := range []string{"a", "b"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					km.Do(key, func() {
+						mu.Lock()
+						n := counts[key]
+						mu.Unlock()
+						mu.Lock()
+						counts[key] = n + 1
+						mu.Unlock()
+					})
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if counts["a"] != 800 || counts["b"] != 800 {
+		t.Errorf("counts = %v, want 800 each", counts)
+	}
+	if km.Len() != 0 {
+		t.Errorf("Len() = %d, unused keys were not released", km.Len())
+	}
+}
+
+func TestKeyedMutex_TryLock(t *testing.T) {
+	km := NewKeyedMutex[int]()
+	if !km.TryLock(1) {
+		t.Fatal("TryLock() on free key failed")
+	}
+	if km.TryLock(1) {
+		t.Error("TryLock() on held key succeeded")
+	}
+	if !km.TryLock(2) {
+		t.Error("TryLock() on another key failed")
+	}
+	km.Unlock(1)
+	km.Unlock(2)
+	if km.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", km.Len())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Unlock() of unlocked key did not panic")
+		}
+	}()
+	km.Unlock(1)
+}
@@ -0,0 +1,155 @@
+package cc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket: tokens are added at a fixed rate up to burst,
+// and each event takes one token.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second, <= 0 means unlimited
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a Limiter allowing rate events per second with bursts of up to burst events.
+// The bucket starts full. A rate <= 0 allows every event.
+func NewLimiter(rate float64, burst int) *Limiter {
+	burst = max(burst, 1)
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// advance refills the bucket up to now. It must be called with l.mu held.
+func (l *Limiter) advance(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+}
+
+// Allow takes a token if one is available and reports whether it did.
+func (l *Limiter) Allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or ctx is done.
+// The token is returned to the bucket if ctx ends first.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	l.advance(time.Now())
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.advance(time.Now())
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Tokens returns the number of tokens currently available, negative when events are queued.
+func (l *Limiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	return l.tokens
+}
+
+// full reports whether the bucket holds burst tokens, i.e. no event is waiting or being paid back.
+func (l *Limiter) full() bool {
+	return l.rate <= 0 || l.Tokens() >= l.burst
+}
+
+type keyedLimiter struct {
+	l        *Limiter
+	lastUsed time.Time
+}
+
+// KeyedLimiter holds one Limiter per key, e.g. per API key or per instId.
+type KeyedLimiter[K comparable] struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    int
+	limiters map[K]*keyedLimiter
+}
+
+// NewKeyedLimiter creates a KeyedLimiter whose limiters all use rate and burst.
+func NewKeyedLimiter[K comparable](rate float64, burst int) *KeyedLimiter[K] {
+	return &KeyedLimiter[K]{rate: rate, burst: burst, limiters: make(map[K]*keyedLimiter)}
+}
+
+// Get returns the limiter of key, creating it if needed.
+func (kl *KeyedLimiter[K]) Get(key K) *Limiter {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+	e, ok := kl.limiters[key]
+	if !ok {
+		e = &keyedLimiter{l: NewLimiter(kl.rate, kl.burst)}
+		kl.limiters[key] = e
+	}
+	e.lastUsed = time.Now()
+	return e.l
+}
+
+func (kl *KeyedLimiter[K]) Allow(key K) bool {
+	return kl.Get(key).Allow()
+}
+
+func (kl *KeyedLimiter[K]) Wait(ctx context.Context, key K) error {
+	return kl.Get(key).Wait(ctx)
+}
+
+// Prune drops the limiters unused for idle whose bucket is full again. Limiters still
+// refilling, including the ones with events waiting, are kept, since a new limiter for
+// their key would start full and allow a burst past the rate.
+// It returns the number of limiters dropped.
+func (kl *KeyedLimiter[K]) Prune(idle time.Duration) int {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+	n := 0
+	for k, e := range kl.limiters {
+		if time.Since(e.lastUsed) >= idle && e.l.full() {
+			delete(kl.limiters, k)
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns the number of limiters.
+func (kl *KeyedLimiter[K]) Len() int {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+	return len(kl.limiters)
+}
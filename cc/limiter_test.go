@@ -0,0 +1,99 @@
+package cc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	l := NewLimiter(100, 3)
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%d within burst = false", i)
+		}
+	}
+	if l.Allow() {
+		t.Error("Allow() beyond burst = true")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("Allow() after refill = false")
+	}
+
+	unlimited := NewLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if !unlimited.Allow() {
+			t.Fatal("Allow() on unlimited limiter = false")
+		}
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := NewLimiter(100, 1)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if d := time.Since(start); d < 15*time.Millisecond {
+		t.Errorf("3 Wait() at 100/s took %v, want >= 20ms", d)
+	}
+
+	l = NewLimiter(1, 1)
+	l.Allow()
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(tctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want DeadlineExceeded", err)
+	}
+	if tok := l.Tokens(); tok < 0 {
+		t.Errorf("Tokens() = %v, cancelled Wait() did not return its token", tok)
+	}
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	kl := NewKeyedLimiter[string](1, 1)
+	if !kl.Allow("a") || kl.Allow("a") {
+		t.Error("key a should allow exactly one event")
+	}
+	if !kl.Allow("b") {
+		t.Error("key b should have its own bucket")
+	}
+	if kl.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", kl.Len())
+	}
+	if n := kl.Prune(0); n != 0 || kl.Len() != 2 {
+		t.Errorf("Prune() of drained limiters = %d, Len() = %d, want 0, 2", n, kl.Len())
+	}
+
+	kl = NewKeyedLimiter[string](1000, 1)
+	kl.Allow("a")
+	kl.Allow("b")
+	time.Sleep(5 * time.Millisecond)
+	if n := kl.Prune(0); n != 2 || kl.Len() != 0 {
+		t.Errorf("Prune() of refilled limiters = %d, Len() = %d, want 2, 0", n, kl.Len())
+	}
+}
+
+func TestKeyedLimiter_PruneWaiting(t *testing.T) {
+	kl := NewKeyedLimiter[string](1, 1)
+	kl.Allow("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- kl.Wait(ctx, "a") }()
+	for kl.Get("a").Tokens() >= 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if n := kl.Prune(0); n != 0 {
+		t.Errorf("Prune() = %d, dropped a limiter with a waiter", n)
+	}
+	if kl.Allow("a") {
+		t.Error("Allow() after Prune() let a burst through")
+	}
+	cancel()
+	<-done
+}
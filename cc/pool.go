@@ -0,0 +1,140 @@
+package cc
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+// Result is the outcome of one task of a Pool.
+type Result[T, R any] struct {
+	Index int // position of the input in the input sequence
+	Input T
+	Value R
+	Err   error
+}
+
+// Pool runs a function over inputs with a fixed number of workers.
+// Panics in the function are recovered and reported as *funcs.PanicError.
+type Pool[T, R any] struct {
+	workers int
+	fn      func(ctx context.Context, input T) (R, error)
+}
+
+// NewPool creates a Pool with the given number of workers, at least 1.
+func NewPool[T, R any](workers int, fn func(ctx context.Context, input T) (R, error)) *Pool[T, R] {
+	return &Pool[T, R]{workers: max(workers, 1), fn: fn}
+}
+
+// Run processes the inputs and yields the results as they complete.
+// Stopping the iteration early, or cancelling ctx, stops feeding inputs
+// and waits for the running tasks to finish. After a cancellation, the results
+// of the tasks that were running are still yielded. Run does not wait for inputs
+// to return: an iterator blocked waiting for its next value, such as Queue.All,
+// is left to stop at its next yield, and that value is dropped.
+func (p *Pool[T, R]) Run(ctx context.Context, inputs iter.Seq[T]) iter.Seq[Result[T, R]] {
+	return func(yield func(Result[T, R]) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		tasks := make(chan Result[T, R])
+		results := make(chan Result[T, R])
+		var wg sync.WaitGroup
+		for i := 0; i < p.workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					var t Result[T, R]
+					var ok bool
+					select {
+					case t, ok = <-tasks:
+						if !ok {
+							return
+						}
+					case <-ctx.Done():
+						return
+					}
+					t.Err = funcs.E(func() (err error) {
+						t.Value, err = p.fn(ctx, t.Input)
+						return err
+					})
+					// always deliver, the consumer drains results until they are closed
+					results <- t
+				}
+			}()
+		}
+		go func() {
+			defer close(tasks)
+			i := 0
+			for input := range inputs {
+				select {
+				case tasks <- Result[T, R]{Index: i, Input: input}:
+				case <-ctx.Done():
+					return
+				}
+				i++
+			}
+		}()
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for r := range results {
+			if !yield(r) {
+				cancel()
+				break
+			}
+		}
+		// drain so that the workers exit
+		for range results {
+		}
+	}
+}
+
+// RunOrdered is like Run but yields the results in input order.
+// Results completed ahead of their turn are buffered.
+func (p *Pool[T, R]) RunOrdered(ctx context.Context, inputs iter.Seq[T]) iter.Seq[Result[T, R]] {
+	return func(yield func(Result[T, R]) bool) {
+		pending := make(map[int]Result[T, R])
+		next := 0
+		for r := range p.Run(ctx, inputs) {
+			pending[r.Index] = r
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ParallelMap applies fn to each input with at most limit concurrent calls and
+// returns the values in input order. The first error to occur, whatever the position
+// of its input, cancels the remaining calls and is returned.
+func ParallelMap[T, R any](ctx context.Context, inputs iter.Seq[T], limit int, fn func(ctx context.Context, input T) (R, error)) ([]R, error) {
+	var rv []R
+	for r := range NewPool(limit, fn).Run(ctx, inputs) {
+		if r.Err != nil {
+			// stopping the iteration cancels the calls still running
+			return nil, r.Err
+		}
+		if n := r.Index + 1 - len(rv); n > 0 {
+			rv = append(rv, make([]R, n)...)
+		}
+		rv[r.Index] = r.Value
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
@@ -0,0 +1,195 @@
+package cc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+func square(ctx context.Context, n int) (int, error) {
+	time.Sleep(time.Duration(10-n%10) * time.Millisecond)
+	return n * n, nil
+}
+
+func TestPool_Run(t *testing.T) {
+	p := NewPool(4, square)
+	var got []int
+	for r := range p.Run(context.Background(), slices.Values([]int{1, 2, 3, 4, 5, 6})) {
+		if r.Err != nil || r.Value != r.Input*r.Input {
+			t.Errorf("Run() result = %+v", r)
+		}
+		got = append(got, r.Value)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 4, 9, 16, 25, 36}) {
+		t.Errorf("Run() values = %v", got)
+	}
+}
+
+func TestPool_RunOrdered(t *testing.T) {
+	p := NewPool(3, square)
+	i := 0
+	for r := range p.RunOrdered(context.Background(), slices.Values([]int{5, 4, 3, 2, 1})) {
+		if r.Index != i {
+			t.Errorf("RunOrdered() index = %d, want %d", r.Index, i)
+		}
+		i++
+	}
+	if i != 5 {
+		t.Errorf("RunOrdered() yielded %d results, want 5", i)
+	}
+}
+
+func TestPool_EarlyStop(t *testing.T) {
+	var calls atomic.Int32
+	p := NewPool(2, func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		return n, nil
+	})
+	inputs := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	for range p.Run(context.Background(), inputs) {
+		break
+	}
+	if n := calls.Load(); n > 10 {
+		t.Errorf("fn called %d times after early stop", n)
+	}
+}
+
+func TestPool_Panic(t *testing.T) {
+	p := NewPool(1, func(ctx context.Context, n int) (int, error) {
+		panic("boom")
+	})
+	for r := range p.Run(context.Background(), slices.Values([]int{1})) {
+		var pe *funcs.PanicError
+		if !errors.As(r.Err, &pe) {
+			t.Errorf("Run() error = %v, want *funcs.PanicError", r.Err)
+		}
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	got, err := ParallelMap(context.Background(), slices.Values([]int{1, 2, 3, 4}), 2, square)
+	if err != nil || !slices.Equal(got, []int{1, 4, 9, 16}) {
+		t.Errorf("ParallelMap() = %v, %v", got, err)
+	}
+
+	want := errors.New("bad input")
+	_, err = ParallelMap(context.Background(), slices.Values([]int{1, 2, 3}), 2, func(ctx context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, want
+		}
+		return n, nil
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("ParallelMap() error = %v, want %v", err, want)
+	}
+}
+
+func TestParallelMap_LateFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	want := errors.New("bad input")
+	start := time.Now()
+	_, err := ParallelMap(ctx, slices.Values([]int{0, 1, 2, 3}), 4, func(ctx context.Context, n int) (int, error) {
+		if n == 3 {
+			return 0, want
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("ParallelMap() error = %v, want %v", err, want)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("ParallelMap() returned after %v, want the late failure to cancel the blocked calls", d)
+	}
+}
+
+func TestParallelMap_BlockingInputs(t *testing.T) {
+	q := NewQueue[int](10)
+	q.Put(context.Background(), 1)
+	q.Put(context.Background(), 2)
+	defer q.Close()
+	want := errors.New("bad input")
+	done := make(chan error)
+	go func() {
+		_, err := ParallelMap(context.Background(), q.All(), 2, func(ctx context.Context, n int) (int, error) {
+			return 0, want
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, want) {
+			t.Errorf("ParallelMap() error = %v, want %v", err, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ParallelMap() did not return while its inputs were blocked")
+	}
+}
+
+func TestPool_RunCancelled(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		n := 0
+		for range NewPool(1, func(ctx context.Context, n int) (int, error) {
+			cancel()
+			return n, nil
+		}).Run(ctx, slices.Values([]int{1})) {
+			n++
+		}
+		if n != 1 {
+			t.Fatalf("Run() yielded %d results after cancel, want the computed one", n)
+		}
+	}
+}
+
+func TestErrGroup(t *testing.T) {
+	g, ctx := NewErrGroup(context.Background(), 2)
+	var running, peak atomic.Int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", p)
+	}
+	if ctx.Err() == nil {
+		t.Error("context not cancelled after Wait()")
+	}
+
+	g, ctx = NewErrGroup(context.Background(), 0)
+	g.Go(func() error { panic("boom") })
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var pe *funcs.PanicError
+	if err := g.Wait(); !errors.As(err, &pe) {
+		t.Errorf("Wait() error = %v, want *funcs.PanicError", err)
+	}
+}
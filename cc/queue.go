@@ -0,0 +1,122 @@
+package cc
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ErrClosed is returned by Queue operations after Close.
+var ErrClosed = errors.New("cc: queue closed")
+
+// Queue is a bounded blocking FIFO queue.
+// After Close, Put fails and Get drains the remaining items before failing.
+type Queue[T any] struct {
+	items  chan T
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewQueue creates a Queue holding at most capacity items.
+func NewQueue[T any](capacity int) *Queue[T] {
+	return &Queue[T]{items: make(chan T, capacity), closed: make(chan struct{})}
+}
+
+// Put adds v, blocking while the queue is full.
+func (q *Queue[T]) Put(ctx context.Context, v T) error {
+	select {
+	case <-q.closed:
+		return ErrClosed
+	default:
+	}
+	select {
+	case q.items <- v:
+		return nil
+	case <-q.closed:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryPut adds v if the queue is open and not full.
+func (q *Queue[T]) TryPut(v T) bool {
+	select {
+	case <-q.closed:
+		return false
+	default:
+	}
+	select {
+	case q.items <- v:
+		return true
+	default:
+		return false
+	}
+}
+
+// Get removes the oldest item, blocking while the queue is empty.
+func (q *Queue[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case v := <-q.items:
+		return v, nil
+	default:
+	}
+	select {
+	case v := <-q.items:
+		return v, nil
+	case <-q.closed:
+		return q.drain()
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// TryGet removes the oldest item if there is one.
+func (q *Queue[T]) TryGet() (T, bool) {
+	select {
+	case v := <-q.items:
+		return v, true
+	default:
+		var zero T
+		return zero, false
+	}
+}
+
+func (q *Queue[T]) drain() (T, error) {
+	select {
+	case v := <-q.items:
+		return v, nil
+	default:
+		var zero T
+		return zero, ErrClosed
+	}
+}
+
+// Close closes the queue. It is safe to call more than once.
+func (q *Queue[T]) Close() {
+	q.once.Do(func() { close(q.closed) })
+}
+
+// Len returns the number of queued items.
+func (q *Queue[T]) Len() int {
+	return len(q.items)
+}
+
+// Cap returns the capacity of the queue.
+func (q *Queue[T]) Cap() int {
+	return cap(q.items)
+}
+
+// All yields items as they are queued until the queue is closed and drained.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, err := q.Get(context.Background())
+			if err != nil || !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	q := NewQueue[int](2)
+	ctx := context.Background()
+	q.Put(ctx, 1)
+	if !q.TryPut(2) || q.TryPut(3) {
+		t.Error("TryPut() should fail only when full")
+	}
+	if q.Len() != 2 || q.Cap() != 2 {
+		t.Errorf("Len(), Cap() = %d, %d, want 2, 2", q.Len(), q.Cap())
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := q.Put(tctx, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Put() on full queue error = %v, want DeadlineExceeded", err)
+	}
+
+	if v, err := q.Get(ctx); err != nil || v != 1 {
+		t.Errorf("Get() = %v, %v, want 1", v, err)
+	}
+	q.Close()
+	q.Close()
+	if err := q.Put(ctx, 4); !errors.Is(err, ErrClosed) {
+		t.Errorf("Put() after Close() error = %v, want ErrClosed", err)
+	}
+	if v, err := q.Get(ctx); err != nil || v != 2 {
+		t.Errorf("Get() after Close() = %v, %v, want remaining 2", v, err)
+	}
+	if _, err := q.Get(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get() on drained queue error = %v, want ErrClosed", err)
+	}
+	if _, ok := q.TryGet(); ok {
+		t.Error("TryGet() on drained queue should fail")
+	}
+}
+
+func TestQueue_All(t *testing.T) {
+	q := NewQueue[int](1)
+	go func() {
+		for i := 0; i < 5; i++ {
+			q.Put(context.Background(), i)
+		}
+		q.Close()
+	}()
+	if got := slices.Collect(q.All()); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("All() = %v", got)
+	}
+}
@@ -0,0 +1,224 @@
+package cc
+
+import (
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+// ShardedMap is a concurrent map split into shards, each guarded by its own lock.
+// It has the same methods as Map, keeps an atomic count so Len is O(1),
+// and adds Compute for atomic read-modify-write.
+// It is better suited than Map to write-heavy workloads.
+// The zero value is ready to use, with the default number of shards.
+type ShardedMap[K comparable, V any] struct {
+	once   sync.Once // creates the shards
+	seed   maphash.Seed
+	shards []shard[K, V]
+	count  atomic.Int64
+}
+
+// NewShardedMap creates a new ShardedMap with the given number of shards,
+// rounded up to a power of two. n <= 0 picks a default based on GOMAXPROCS.
+func NewShardedMap[K comparable, V any](n int) *ShardedMap[K, V] {
+	m := &ShardedMap[K, V]{}
+	m.once.Do(func() { m.setup(n) })
+	return m
+}
+
+func (m *ShardedMap[K, V]) setup(n int) {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	m.seed = maphash.MakeSeed()
+	m.shards = make([]shard[K, V], size)
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+}
+
+// init sets up the shards of a zero ShardedMap.
+func (m *ShardedMap[K, V]) init() {
+	m.once.Do(func() { m.setup(0) })
+}
+
+func (m *ShardedMap[K, V]) shard(key K) *shard[K, V] {
+	m.init()
+	h := maphash.Comparable(m.seed, key)
+	return &m.shards[h&uint64(len(m.shards)-1)]
+}
+
+func (m *ShardedMap[K, V]) Load(key K) (value V, ok bool) {
+	s := m.shard(key)
+	s.RLock()
+	defer s.RUnlock()
+	value, ok = s.m[key]
+	return value, ok
+}
+
+func (m *ShardedMap[K, V]) Store(key K, value V) {
+	m.Swap(key, value)
+}
+
+func (m *ShardedMap[K, V]) Delete(key K) {
+	m.LoadAndDelete(key)
+}
+
+// Range calls f for each key and value. Like Map.Range, it does not hold a lock
+// while calling f, so f may modify the map.
+func (m *ShardedMap[K, V]) Range(f func(key K, value V) bool) {
+	m.init()
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		keys := make([]K, 0, len(s.m))
+		values := make([]V, 0, len(s.m))
+		for k, v := range s.m {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		s.RUnlock()
+		for j := range keys {
+			if !f(keys[j], values[j]) {
+				return
+			}
+		}
+	}
+}
+
+func (m *ShardedMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	m.count.Add(1)
+	return value, false
+}
+
+func (m *ShardedMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	value, loaded = s.m[key]
+	if loaded {
+		delete(s.m, key)
+		m.count.Add(-1)
+	}
+	return value, loaded
+}
+
+// CompareAndSwap swaps the value for key if it equals old.
+// Like sync.Map, it panics if V is not comparable.
+func (m *ShardedMap[K, V]) CompareAndSwap(key K, old, new V) bool {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if v, ok := s.m[key]; !ok || any(v) != any(old) {
+		return false
+	}
+	s.m[key] = new
+	return true
+}
+
+// CompareAndDelete deletes the entry for key if its value equals old.
+// Like sync.Map, it panics if V is not comparable.
+func (m *ShardedMap[K, V]) CompareAndDelete(key K, old V) bool {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if v, ok := s.m[key]; !ok || any(v) != any(old) {
+		return false
+	}
+	delete(s.m, key)
+	m.count.Add(-1)
+	return true
+}
+
+func (m *ShardedMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	previous, loaded = s.m[key]
+	s.m[key] = value
+	if !loaded {
+		m.count.Add(1)
+	}
+	return previous, loaded
+}
+
+// Compute atomically updates the entry for key. fn receives the current value
+// and whether it exists, and returns the new value and whether to keep it;
+// returning false deletes the entry. Compute returns the result of fn.
+// fn runs with the shard locked and must not call back into the map.
+func (m *ShardedMap[K, V]) Compute(key K, fn func(old V, ok bool) (V, bool)) (value V, ok bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	old, loaded := s.m[key]
+	value, ok = fn(old, loaded)
+	switch {
+	case ok:
+		s.m[key] = value
+		if !loaded {
+			m.count.Add(1)
+		}
+	case loaded:
+		delete(s.m, key)
+		m.count.Add(-1)
+	}
+	return value, ok
+}
+
+func (m *ShardedMap[K, V]) Clear() {
+	m.init()
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.Lock()
+		m.count.Add(-int64(len(s.m)))
+		clear(s.m)
+		s.Unlock()
+	}
+}
+
+// Len returns the number of entries in O(1).
+func (m *ShardedMap[K, V]) Len() int {
+	return int(m.count.Load())
+}
+
+func (m *ShardedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.Range(func(key K, value V) bool {
+			return yield(key)
+		})
+	}
+}
+
+func (m *ShardedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.Range(func(key K, value V) bool {
+			return yield(value)
+		})
+	}
+}
+
+func (m *ShardedMap[K, V]) Items() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.Range(func(key K, value V) bool {
+			return yield(key, value)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package cc
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestShardedMap_Basic(t *testing.T) {
+	m := NewShardedMap[string, int](3)
+	if len(m.shards) != 4 {
+		t.Errorf("shards = %d, want 4", len(m.shards))
+	}
+
+	m.Store("a", 1)
+	m.Store("a", 2)
+	if v, ok := m.Load("a"); !ok || v != 2 {
+		t.Errorf("Load(a) = %v, %v, want 2, true", v, ok)
+	}
+	if v, loaded := m.LoadOrStore("b", 3); loaded || v != 3 {
+		t.Errorf("LoadOrStore(b) = %v, %v, want 3, false", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("b", 4); !loaded || v != 3 {
+		t.Errorf("LoadOrStore(b) = %v, %v, want 3, true", v, loaded)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+
+	if m.CompareAndSwap("a", 1, 10) {
+		t.Error("CompareAndSwap() with wrong old value succeeded")
+	}
+	if !m.CompareAndSwap("a", 2, 10) {
+		t.Error("CompareAndSwap() failed")
+	}
+	if m.CompareAndDelete("a", 2) || !m.CompareAndDelete("a", 10) {
+		t.Error("CompareAndDelete() mismatch")
+	}
+	if prev, loaded := m.Swap("b", 5); !loaded || prev != 3 {
+		t.Errorf("Swap(b) = %v, %v, want 3, true", prev, loaded)
+	}
+	if v, loaded := m.LoadAndDelete("b"); !loaded || v != 5 {
+		t.Errorf("LoadAndDelete(b) = %v, %v, want 5, true", v, loaded)
+	}
+	m.Delete("missing")
+	if m.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", m.Len())
+	}
+}
+
+func TestShardedMap_Iterators(t *testing.T) {
+	m := NewShardedMap[int, int](0)
+	for i := 0; i < 100; i++ {
+		m.Store(i, i*i)
+	}
+	keys := slices.Sorted(m.Keys())
+	if len(keys) != 100 || keys[0] != 0 || keys[99] != 99 {
+		t.Errorf("Keys() = %v", keys)
+	}
+	sum := 0
+	for v := range m.Values() {
+		sum += v
+	}
+	if sum != 328350 {
+		t.Errorf("sum of Values() = %d, want 328350", sum)
+	}
+	for k, v := range m.Items() {
+		if v != k*k {
+			t.Errorf("Items() %d = %d", k, v)
+		}
+		m.Delete(k) // Range does not hold a lock while yielding
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", m.Len())
+	}
+
+	m.Store(1, 1)
+	m.Clear()
+	if m.Len() != 0 {
+		t.Errorf("Len() after Clear() = %d, want 0", m.Len())
+	}
+}
+
+func TestShardedMap_Compute(t *testing.T) {
+	m := NewShardedMap[string, int](0)
+	incr := func(old int, ok bool) (int, bool) { return old + 1, true }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				m.Compute("n", incr)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Load("n"); v != 8000 {
+		t.Errorf("Load(n) = %d, want 8000", v)
+	}
+
+	if _, ok := m.Compute("n", func(old int, ok bool) (int, bool) { return 0, false }); ok {
+		t.Error("Compute() returning false should report ok = false")
+	}
+	if _, ok := m.Load("n"); ok || m.Len() != 0 {
+		t.Error("Compute() returning false should delete the key")
+	}
+}
+
+func benchmarkWrites(b *testing.B, store func(key string, value int)) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			store(keys[i%len(keys)], i)
+			i++
+		}
+	})
+}
+
+func BenchmarkWriteHeavy_Map(b *testing.B) {
+	m := NewMap[string, int]()
+	benchmarkWrites(b, func(key string, value int) {
+		m.Store(key, value)
+		if value%16 == 0 {
+			m.Len()
+		}
+	})
+}
+
+func BenchmarkWriteHeavy_ShardedMap(b *testing.B) {
+	m := NewShardedMap[string, int](0)
+	benchmarkWrites(b, func(key string, value int) {
+		m.Store(key, value)
+		if value%16 == 0 {
+			m.Len()
+		}
+	})
+}
+
+func BenchmarkStore_Map(b *testing.B) {
+	m := NewMap[string, int]()
+	benchmarkWrites(b, m.Store)
+}
+
+func BenchmarkStore_ShardedMap(b *testing.B) {
+	m := NewShardedMap[string, int](0)
+	benchmarkWrites(b, m.Store)
+}
+
+func TestShardedMap_ZeroValue(t *testing.T) {
+	var m ShardedMap[string, int]
+	if _, ok := m.Load("a"); ok {
+		t.Error("Load() on a zero ShardedMap found a value")
+	}
+	m.Store("a", 1)
+	if v, ok := m.Load("a"); !ok || v != 1 || m.Len() != 1 {
+		t.Errorf("Load(a) = %v, %v, Len() = %d", v, ok, m.Len())
+	}
+	var zero ShardedMap[string, int]
+	zero.Range(func(string, int) bool { return true })
+	zero.Clear()
+}
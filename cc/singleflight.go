@@ -0,0 +1,115 @@
+package cc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+type flight[V any] struct {
+	done    chan struct{} // closed once val and err are set
+	val     V
+	err     error
+	dups    int
+	waiters int                // callers still waiting, see DoContext
+	cancel  context.CancelFunc // cancels the context of a DoContext call
+}
+
+// Group coalesces concurrent calls with the same key into a single execution,
+// like golang.org/x/sync/singleflight with typed keys and values.
+// A panic in the function is recovered and returned to every caller as *funcs.PanicError.
+// The zero value is ready to use.
+type Group[K comparable, V any] struct {
+	mu      sync.Mutex
+	flights map[K]*flight[V]
+}
+
+// join returns the call in flight for key, or a new one for the caller to run.
+// It must be called with g.mu held.
+func (g *Group[K, V]) join(key K) (f *flight[V], ok bool) {
+	if g.flights == nil {
+		g.flights = make(map[K]*flight[V])
+	}
+	if f, ok = g.flights[key]; ok {
+		f.dups++
+	} else {
+		f = &flight[V]{done: make(chan struct{})}
+		g.flights[key] = f
+	}
+	f.waiters++
+	return f, ok
+}
+
+// run calls fn for f and publishes its result.
+func (g *Group[K, V]) run(key K, f *flight[V], fn func() (V, error)) {
+	f.err = funcs.E(func() (err error) {
+		f.val, err = fn()
+		return err
+	})
+	g.mu.Lock()
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+	g.mu.Unlock()
+	close(f.done)
+}
+
+// Do runs fn for key unless a call for key is already in flight, in which case
+// it waits for that call and returns its result. shared reports whether the
+// result was given to more than one caller.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	f, ok := g.join(key)
+	g.mu.Unlock()
+	if !ok {
+		g.run(key, f, fn)
+	}
+	<-f.done
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return f.val, f.err, f.dups > 0
+}
+
+// DoContext is like Do for a function taking a context. fn runs on its own goroutine
+// with a context that has the values of the ctx of the first caller but not its deadline,
+// so that a caller giving up does not fail the others. Each caller waits for the result
+// or for its own ctx to be done, returning ctx.Err(). The context of fn is cancelled
+// once every caller has given up, and the next call for key starts afresh.
+func (g *Group[K, V]) DoContext(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	f, ok := g.join(key)
+	if !ok {
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f.cancel = cancel
+		go func() {
+			defer cancel()
+			g.run(key, f, func() (V, error) { return fn(fctx) })
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return f.val, f.err, f.dups > 0
+	case <-ctx.Done():
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if f.waiters--; f.waiters == 0 && f.cancel != nil {
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		return v, ctx.Err(), f.dups > 0
+	}
+}
+
+// Forget makes the next Do for key run fn instead of joining the call in flight.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.flights, key)
+}
@@ -0,0 +1,155 @@
+package cc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+func TestGroup_Do(t *testing.T) {
+	var g Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, s := g.Do("k", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if v != 42 || err != nil {
+				t.Errorf("Do() = %v, %v, want 42, nil", v, err)
+			}
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	if n := shared.Load(); n != 10 {
+		t.Errorf("shared results = %d, want 10", n)
+	}
+
+	// a finished call is not reused
+	v, _, s := g.Do("k", func() (int, error) { return 7, nil })
+	if v != 7 || s {
+		t.Errorf("Do() after completion = %v, shared %v, want 7, false", v, s)
+	}
+}
+
+func TestGroup_Panic(t *testing.T) {
+	var g Group[int, int]
+	_, err, _ := g.Do(1, func() (int, error) { panic("boom") })
+	var pe *funcs.PanicError
+	if !errors.As(err, &pe) {
+		t.Errorf("Do() error = %v, want *funcs.PanicError", err)
+	}
+}
+
+func TestGroup_Forget(t *testing.T) {
+	var g Group[int, int]
+	release := make(chan struct{})
+	go g.Do(1, func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	time.Sleep(10 * time.Millisecond)
+	g.Forget(1)
+	if v, _, _ := g.Do(1, func() (int, error) { return 2, nil }); v != 2 {
+		t.Errorf("Do() after Forget() = %d, want 2", v)
+	}
+	close(release)
+}
+
+func TestGroup_DoContext(t *testing.T) {
+	var g Group[string, int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		close(started)
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	// the first caller giving up does not fail the second one
+	first, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		_, err, _ := g.DoContext(first, "k", fn)
+		errc <- err
+	}()
+	<-started
+	result := make(chan int)
+	go func() {
+		v, _, _ := g.DoContext(context.Background(), "k", fn)
+		result <- v
+	}()
+	for {
+		g.mu.Lock()
+		n := g.flights["k"].waiters
+		g.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("DoContext() of the cancelled caller error = %v", err)
+	}
+
+	// a later caller honors its own deadline
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err, shared := g.DoContext(short, "k", fn); !errors.Is(err, context.DeadlineExceeded) || !shared {
+		t.Errorf("DoContext() with a deadline = %v, shared %v", err, shared)
+	}
+
+	close(release)
+	if v := <-result; v != 1 {
+		t.Errorf("DoContext() = %d, want 1", v)
+	}
+}
+
+func TestGroup_DoContextAllGaveUp(t *testing.T) {
+	var g Group[string, int]
+	cancelled := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+	g.DoContext(ctx, "k", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return 0, ctx.Err()
+	})
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the context of fn was not cancelled once every caller gave up")
+	}
+	if v, err, _ := g.DoContext(context.Background(), "k", func(ctx context.Context) (int, error) { return 2, nil }); v != 2 || err != nil {
+		t.Errorf("DoContext() after every caller gave up = %d, %v", v, err)
+	}
+}
@@ -0,0 +1,90 @@
+package codec
+
+import (
+	"errors"
+	"mime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownCodec is returned when no codec is registered for a name or content type.
+var ErrUnknownCodec = errors.New("codec: unknown codec")
+
+// Codec encodes values to bytes and back.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	// ContentType returns the MIME type of the encoding, e.g. "application/json".
+	ContentType() string
+}
+
+var registry = struct {
+	sync.RWMutex
+	byName map[string]Codec
+	byType map[string]Codec
+}{byName: make(map[string]Codec), byType: make(map[string]Codec)}
+
+func init() {
+	Register("json", JSON)
+	Register("gob", Gob)
+	Register("msgpack", MsgPack, "application/x-msgpack", "application/vnd.msgpack")
+}
+
+// Register makes c available under name, its content type and the extra content types.
+// It replaces any codec previously registered under the same keys.
+func Register(name string, c Codec, contentTypes ...string) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.byName[strings.ToLower(name)] = c
+	for _, ct := range append([]string{c.ContentType()}, contentTypes...) {
+		registry.byType[mediaType(ct)] = c
+	}
+}
+
+// Get returns the codec registered under name, case-insensitively.
+func Get(name string) (Codec, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	if c, ok := registry.byName[strings.ToLower(name)]; ok {
+		return c, nil
+	}
+	return nil, ErrUnknownCodec
+}
+
+// ForContentType returns the codec of a Content-Type header value. Parameters such as
+// charset are ignored, and structured syntax suffixes like "application/problem+json"
+// fall back to the codec of the suffix.
+func ForContentType(contentType string) (Codec, error) {
+	mt := mediaType(contentType)
+	registry.RLock()
+	defer registry.RUnlock()
+	if c, ok := registry.byType[mt]; ok {
+		return c, nil
+	}
+	if i := strings.LastIndexByte(mt, '+'); i >= 0 {
+		if c, ok := registry.byName[mt[i+1:]]; ok {
+			return c, nil
+		}
+	}
+	return nil, ErrUnknownCodec
+}
+
+// Names returns the registered codec names, sorted.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.byName))
+	for name := range registry.byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,76 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name    string            `json:"name"`
+	Price   float64           `json:"price"`
+	Tags    []string          `json:"tags,omitempty"`
+	Attrs   map[string]int    `json:"attrs"`
+	Created time.Time         `json:"created"`
+	Next    *testItem         `json:"next,omitempty"`
+	Meta    map[string]string `msgpack:"-"`
+}
+
+func TestRegistry(t *testing.T) {
+	for _, tt := range []struct {
+		contentType string
+		want        Codec
+	}{
+		{"application/json", JSON},
+		{"Application/JSON; charset=utf-8", JSON},
+		{"application/problem+json", JSON},
+		{"application/x-msgpack", MsgPack},
+		{"application/x-gob", Gob},
+	} {
+		c, err := ForContentType(tt.contentType)
+		if err != nil || c != tt.want {
+			t.Errorf("ForContentType(%q) = %v, %v", tt.contentType, c, err)
+		}
+	}
+	if _, err := ForContentType("text/html"); err != ErrUnknownCodec {
+		t.Errorf("ForContentType(text/html) err = %v, want ErrUnknownCodec", err)
+	}
+	if c, err := Get("MsgPack"); err != nil || c != MsgPack {
+		t.Errorf("Get(MsgPack) = %v, %v", c, err)
+	}
+	if got := Names(); !reflect.DeepEqual(got, []string{"gob", "json", "msgpack"}) {
+		t.Errorf("Names() = %v", got)
+	}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	in := testItem{
+		Name:    "BTC-USDT",
+		Price:   65000.5,
+		Tags:    []string{"spot"},
+		Attrs:   map[string]int{"lot": 1},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Next:    &testItem{Name: "ETH-USDT", Attrs: map[string]int{}},
+	}
+	for _, c := range []Codec{JSON, Gob, MsgPack} {
+		bs, err := c.Marshal(in)
+		if err != nil {
+			t.Fatalf("%s: Marshal() error = %v", c.ContentType(), err)
+		}
+		var out testItem
+		if err := c.Unmarshal(bs, &out); err != nil {
+			t.Fatalf("%s: Unmarshal() error = %v", c.ContentType(), err)
+		}
+		if !out.Created.Equal(in.Created) {
+			t.Errorf("%s: Created = %v, want %v", c.ContentType(), out.Created, in.Created)
+		}
+		out.Created = in.Created
+		if out.Next != nil && len(out.Next.Attrs) == 0 {
+			// gob drops empty maps
+			out.Next.Attrs = in.Next.Attrs
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Errorf("%s: round trip = %+v, want %+v", c.ContentType(), out, in)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package csvx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/chenyan/wheels/internal/reflectx"
+)
+
+// Opts configures reading and writing. The zero value is comma separated with a header row.
+type Opts struct {
+	Comma      rune           // field delimiter, default ','
+	NoHeader   bool           // no header row: columns follow the field order of the struct
+	TimeLayout string         // default layout of time fields, default time.RFC3339
+	Location   *time.Location // location of times parsed without a zone, default UTC
+}
+
+// TSV is the Opts of tab separated values.
+var TSV = &Opts{Comma: '\t'}
+
+func (o *Opts) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+func withDefaults(o *Opts) *Opts {
+	var rv Opts
+	if o != nil {
+		rv = *o
+	}
+	if rv.Comma == 0 {
+		rv.Comma = ','
+	}
+	if rv.TimeLayout == "" {
+		rv.TimeLayout = time.RFC3339
+	}
+	return &rv
+}
+
+// RowError is an error decoding one row. Row counts the records of the input from 1,
+// the header included.
+type RowError struct {
+	Row    int
+	Column string // empty when the error is not about a single column
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("csvx: row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("csvx: row %d, column %s: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Writer encodes structs of type T as rows. The header row is written before the first row.
+type Writer[T any] struct {
+	w      *csv.Writer
+	o      *Opts
+	fields []field
+	header bool
+	row    []string
+}
+
+// NewWriter creates a Writer to w. opts may be nil.
+func NewWriter[T any](w io.Writer, opts *Opts) *Writer[T] {
+	o := withDefaults(opts)
+	cw := csv.NewWriter(w)
+	cw.Comma = o.Comma
+	fs := fields(structType[T]())
+	return &Writer[T]{w: cw, o: o, fields: fs, header: o.NoHeader, row: make([]string, len(fs))}
+}
+
+// Write encodes v as one row.
+func (w *Writer[T]) Write(v T) error {
+	if !w.header {
+		if err := w.w.Write(Header[T]()); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return errors.New("csvx: cannot write a nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	for i := range w.fields {
+		f := &w.fields[i]
+		w.row[i] = ""
+		fv, ok := reflectx.FieldByIndex(rv, f.index, false)
+		if !ok {
+			continue
+		}
+		s, err := format(fv, f, w.o)
+		if err != nil {
+			return fmt.Errorf("csvx: column %s: %w", f.name, err)
+		}
+		w.row[i] = s
+	}
+	return w.w.Write(w.row)
+}
+
+// WriteAll writes the values of seq and flushes, stopping at the first error.
+func (w *Writer[T]) WriteAll(seq iter.Seq[T]) error {
+	for v := range seq {
+		if err := w.Write(v); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes out the buffered rows.
+func (w *Writer[T]) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// ReadAll decodes the rows of r into values of type T, which must be a struct or a
+// pointer to one. With a header row, columns are matched to fields by name, then
+// case-insensitively, and unknown columns are ignored. A row that fails to decode yields
+// a *RowError and the iteration goes on; a malformed input yields a *RowError and ends it.
+func ReadAll[T any](r io.Reader, opts *Opts) iter.Seq2[T, error] {
+	o := withDefaults(opts)
+	return func(yield func(T, error) bool) {
+		cr := csv.NewReader(r)
+		cr.Comma = o.Comma
+		cr.FieldsPerRecord = -1
+		cr.ReuseRecord = true
+
+		var zero T
+		fs := fields(structType[T]())
+		cols := make([]*field, len(fs)) // field of each column, nil if ignored
+		for i := range fs {
+			cols[i] = &fs[i]
+		}
+		row := 0
+		for {
+			rec, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			row++
+			if err != nil {
+				yield(zero, &RowError{Row: row, Err: err})
+				return
+			}
+			if row == 1 && !o.NoHeader {
+				cols = mapHeader(rec, fs)
+				continue
+			}
+			v, err := decodeRow[T](rec, cols, o, row)
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// mapHeader returns the field of each column of the header.
+func mapHeader(header []string, fs []field) []*field {
+	cols := make([]*field, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // byte order mark of Excel exports
+		}
+		for j := range fs {
+			if fs[j].name == name {
+				cols[i] = &fs[j]
+				break
+			}
+			if cols[i] == nil && strings.EqualFold(fs[j].name, name) {
+				cols[i] = &fs[j]
+			}
+		}
+	}
+	return cols
+}
+
+func decodeRow[T any](rec []string, cols []*field, o *Opts, row int) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() == reflect.Pointer {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		rv = rv.Elem()
+	}
+	for i, s := range rec {
+		if i >= len(cols) || cols[i] == nil {
+			continue
+		}
+		f := cols[i]
+		fv, _ := reflectx.FieldByIndex(rv, f.index, true)
+		if err := parse(fv, s, f, o); err != nil {
+			return v, &RowError{Row: row, Column: f.name, Err: err}
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,125 @@
+package csvx
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenyan/wheels/store/dbx"
+)
+
+type candle struct {
+	Ts    time.Time     `csv:"ts,layout=2006-01-02 15:04"`
+	Inst  string        `json:"instId"`
+	Open  float64       `csv:"o,prec=2"`
+	Vol   *int64        `db:"vol"`
+	Span  time.Duration `csv:"span"`
+	Done  bool
+	note  string
+	Debug string `csv:"-"`
+}
+
+type order struct {
+	dbx.BaseModel
+	Side string `json:"side"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	vol := int64(42)
+	in := []candle{
+		{Ts: time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC), Inst: "BTC-USDT", Open: 65000.456, Vol: &vol, Span: time.Minute, Done: true},
+		{Inst: "ETH, \"USDT\"", Open: 1},
+	}
+	var buf bytes.Buffer
+	if err := NewWriter[candle](&buf, nil).WriteAll(slices.Values(in)); err != nil {
+		t.Fatal(err)
+	}
+	want := "ts,instId,o,vol,span,Done\n" +
+		"2024-05-01 08:30,BTC-USDT,65000.46,42,1m0s,true\n" +
+		",\"ETH, \"\"USDT\"\"\",1.00,,0s,false\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	var out []candle
+	for v, err := range ReadAll[candle](&buf, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, v)
+	}
+	if len(out) != 2 || !out[0].Ts.Equal(in[0].Ts) || *out[0].Vol != 42 || out[0].Span != time.Minute ||
+		out[1].Inst != in[1].Inst || out[1].Vol != nil || out[0].Open != 65000.46 {
+		t.Errorf("ReadAll() = %+v", out)
+	}
+}
+
+func TestReadAll_Header(t *testing.T) {
+	in := "\ufeffDONE\tunknown\tinstId\tvol\n" +
+		"true\tx\tBTC-USDT\t1\n" +
+		"maybe\tx\tETH-USDT\t2\n" +
+		"false\tx\tSOL-USDT\n"
+	var insts []string
+	var rowErr *RowError
+	for v, err := range ReadAll[*candle](strings.NewReader(in), TSV) {
+		if err != nil {
+			if !errors.As(err, &rowErr) {
+				t.Fatal(err)
+			}
+			continue
+		}
+		insts = append(insts, v.Inst)
+	}
+	if !slices.Equal(insts, []string{"BTC-USDT", "SOL-USDT"}) {
+		t.Errorf("insts = %v", insts)
+	}
+	if rowErr == nil || rowErr.Row != 3 || rowErr.Column != "Done" {
+		t.Errorf("RowError = %v, want row 3, column Done", rowErr)
+	}
+}
+
+func TestNoHeader_Embedded(t *testing.T) {
+	if got := Header[order](); !slices.Equal(got, []string{"id", "status", "ctime", "mtime", "side"}) {
+		t.Errorf("Header() = %v", got)
+	}
+	var buf bytes.Buffer
+	w := NewWriter[order](&buf, &Opts{NoHeader: true, Comma: ';'})
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.Write(order{BaseModel: dbx.BaseModel{ID: 7, Status: 1, Ctime: ts, Mtime: ts}, Side: "buy"})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "7;1;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;buy\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+	for v, err := range ReadAll[order](&buf, &Opts{NoHeader: true, Comma: ';'}) {
+		if err != nil || v.ID != 7 || v.Status != 1 || v.Side != "buy" || v.Mtime.Year() != 2024 {
+			t.Errorf("ReadAll() = %+v, %v", v, err)
+		}
+	}
+}
+
+type inlineOrder struct {
+	dbx.BaseModel `db:",inline"`
+	Side          string `db:"side" json:"side"`
+}
+
+func TestInlineEmbedded(t *testing.T) {
+	if got := Header[inlineOrder](); !slices.Equal(got, []string{"id", "status", "ctime", "mtime", "side"}) {
+		t.Errorf("Header() = %v", got)
+	}
+	var buf bytes.Buffer
+	w := NewWriter[inlineOrder](&buf, nil)
+	w.Write(inlineOrder{BaseModel: dbx.BaseModel{ID: 7, Status: 2}, Side: "sell"})
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	for v, err := range ReadAll[inlineOrder](&buf, nil) {
+		if err != nil || v.ID != 7 || v.Status != 2 || v.Side != "sell" {
+			t.Errorf("ReadAll() = %+v, %v", v, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package csvx encodes and decodes CSV and TSV rows to structs, driven by struct tags.
+//
+// A field is named by its csv tag, falling back to its json tag, then to its db tag,
+// so the tags of types like dbx.BaseModel are reused. The name "-" in the csv tag skips
+// the field; in the json tag it selects the db tag, if any, since json:"-" commonly hides
+// database columns from APIs. Options follow the name, separated by commas:
+//
+//	Ctime time.Time `csv:"ctime,layout=2006-01-02 15:04:05"` // time layout, default Opts.TimeLayout
+//	Px    float64   `csv:"px,prec=2"`                       // digits after the decimal point
+//
+// Supported field types are strings, booleans, integers, floats, time.Time, time.Duration,
+// types implementing encoding.TextMarshaler and encoding.TextUnmarshaler, and pointers
+// to these. Embedded structs are flattened unless their tag has a name, or when it has the
+// inline option, as in db:",inline".
+package csvx
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type field struct {
+	name   string
+	index  []int
+	layout string // time layout
+	prec   int    // float precision, -1 for the shortest representation
+}
+
+var fieldCache sync.Map // reflect.Type -> []field
+
+func fields(t reflect.Type) []field {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.([]field)
+	}
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("csv")
+		if !ok {
+			for _, key := range []string{"json", "db"} {
+				if v, ok := sf.Tag.Lookup(key); ok {
+					tag = v
+					if name, _, _ := strings.Cut(v, ","); name != "-" {
+						break
+					}
+				}
+			}
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if name == "-" {
+			continue
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && (name == "" || slices.Contains(parts[1:], "inline")) && ft.Kind() == reflect.Struct {
+			for _, f := range fields(ft) {
+				f.index = append([]int{i}, f.index...)
+				fs = append(fs, f)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		f := field{name: name, index: []int{i}, prec: -1}
+		for _, opt := range parts[1:] {
+			k, v, _ := strings.Cut(opt, "=")
+			switch k {
+			case "layout":
+				f.layout = v
+			case "prec":
+				if n, err := strconv.Atoi(v); err == nil {
+					f.prec = n
+				}
+			}
+		}
+		fs = append(fs, f)
+	}
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+// Header returns the column names of T.
+func Header[T any]() []string {
+	fs := fields(structType[T]())
+	names := make([]string, len(fs))
+	for i, f := range fs {
+		names[i] = f.name
+	}
+	return names
+}
+
+func structType[T any]() reflect.Type {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic("csvx: " + t.String() + " is not a struct")
+	}
+	return t
+}
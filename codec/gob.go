@@ -0,0 +1,28 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Gob encodes values with encoding/gob. Each value is encoded with its own type
+// description, so it suits caches and files rather than streams of many small values.
+var Gob Codec = gobCodec{}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (gobCodec) ContentType() string {
+	return "application/x-gob"
+}
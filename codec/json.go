@@ -5,6 +5,23 @@ import (
 	"log"
 )
 
+// JSON encodes values with encoding/json.
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
 func JSONString(v any) string {
 	bs, err := json.Marshal(v)
 	if err != nil {
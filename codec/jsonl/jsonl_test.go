@@ -0,0 +1,131 @@
+package jsonl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type record struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestReadAll(t *testing.T) {
+	in := "{\"id\":1,\"name\":\"a\"}\n\n  \r\n{\"id\":2}\r\nnot json\n{\"id\":3}"
+	var ids []int
+	var lineErr *LineError
+	for v, err := range ReadAll[record](strings.NewReader(in)) {
+		if err != nil {
+			if !errors.As(err, &lineErr) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			continue
+		}
+		ids = append(ids, v.ID)
+	}
+	if len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("ids = %v, want [1 2 3]", ids)
+	}
+	if lineErr == nil || lineErr.Line != 5 {
+		t.Errorf("LineError = %v, want line 5", lineErr)
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	for _, name := range []string{"out.jsonl", "out.jsonl.gz"} {
+		path := filepath.Join(t.TempDir(), name)
+		w, err := Create[record](path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range 100 {
+			if err := w.Write(record{ID: i, Name: "<x>"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if w.Count() != 100 {
+			t.Errorf("%s: Count() = %d, want 100", name, w.Count())
+		}
+		n := 0
+		for v, err := range ReadFile[record](path) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.ID != n || v.Name != "<x>" {
+				t.Errorf("%s: line %d = %+v", name, n, v)
+			}
+			n++
+		}
+		if n != 100 {
+			t.Errorf("%s: read %d lines, want 100", name, n)
+		}
+	}
+}
+
+func TestGzipWriter_Flush(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewGzipWriter[record](&buf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(record{ID: 1})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Flush() wrote nothing to the underlying writer")
+	}
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tail.jsonl")
+	if err := os.WriteFile(path, []byte("{\"id\":0}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	appendTo := func(s string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.WriteString(s)
+		f.Close()
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		appendTo("{\"id\":1}\n{\"id\"")
+		time.Sleep(20 * time.Millisecond)
+		appendTo(":2}\n")
+		time.Sleep(20 * time.Millisecond)
+		// rotate
+		os.Rename(path, path+".1")
+		os.WriteFile(path, []byte("{\"id\":3}\n"), 0o644)
+	}()
+
+	var ids []int
+	for v, err := range Tail[record](ctx, path, &TailOpts{PollInterval: 5 * time.Millisecond}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, v.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+}
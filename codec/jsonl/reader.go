@@ -0,0 +1,111 @@
+// Package jsonl reads and writes JSON Lines (https://jsonlines.org), one JSON value per line.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+)
+
+// LineError is an error about one line of the input, numbered from 1.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("jsonl: line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// lineReader reads whole lines of any length, keeping track of the line number.
+type lineReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// next returns the next line without its line ending. A last line without
+// a trailing newline is returned with a nil error, and io.EOF follows.
+func (lr *lineReader) next() ([]byte, error) {
+	bs, err := lr.r.ReadBytes('\n')
+	if len(bs) == 0 && err != nil {
+		return nil, err
+	}
+	lr.line++
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return bytes.TrimRight(bs, "\r\n"), nil
+}
+
+// ReadAll decodes r line by line into values of type T. Blank lines are skipped.
+// A line that fails to decode yields a *LineError and the iteration goes on,
+// so the caller decides whether to stop. A read error yields a *LineError and ends it.
+func ReadAll[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		lr := &lineReader{r: bufio.NewReader(r)}
+		for {
+			bs, err := lr.next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			var v T
+			if err != nil {
+				yield(v, &LineError{Line: lr.line + 1, Err: err})
+				return
+			}
+			if len(bytes.TrimSpace(bs)) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(bs, &v); err != nil {
+				if !yield(v, &LineError{Line: lr.line, Err: err}) {
+					return
+				}
+				continue
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ReadFile is like ReadAll over the file name, decompressed if its name ends with ".gz".
+// The file is closed when the iteration ends.
+func ReadFile[T any](name string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		f, err := os.Open(name)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		defer f.Close()
+		var r io.Reader = f
+		if strings.HasSuffix(name, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			defer gz.Close()
+			r = gz
+		}
+		for v, err := range ReadAll[T](r) {
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package jsonl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"time"
+)
+
+// TailOpts configures Tail.
+type TailOpts struct {
+	FromStart    bool          // read the existing lines first instead of starting at the end
+	PollInterval time.Duration // delay between checks for new lines, default 200ms
+}
+
+// Tail follows the file name like tail -F, decoding each line appended to it.
+// Partial lines are held until their newline is written. When the file is
+// truncated it is read again from the start, and when it is replaced, e.g. by
+// log rotation, the new file is read from its start. Decode errors yield a
+// *LineError and the iteration goes on. Tail ends when ctx is done, or on an
+// error opening or reading the file.
+func Tail[T any](ctx context.Context, name string, opts *TailOpts) iter.Seq2[T, error] {
+	var o TailOpts
+	if opts != nil {
+		o = *opts
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 200 * time.Millisecond
+	}
+	return func(yield func(T, error) bool) {
+		t := &tailer{name: name}
+		var zero T
+		if err := t.open(!o.FromStart); err != nil {
+			yield(zero, err)
+			return
+		}
+		defer func() { t.f.Close() }()
+
+		ticker := time.NewTicker(o.PollInterval)
+		defer ticker.Stop()
+		for {
+			lines, err := t.read()
+			for _, bs := range lines {
+				t.line++
+				if len(bytes.TrimSpace(bs)) == 0 {
+					continue
+				}
+				var v T
+				if err := json.Unmarshal(bs, &v); err != nil {
+					if !yield(v, &LineError{Line: t.line, Err: err}) {
+						return
+					}
+					continue
+				}
+				if !yield(v, nil) {
+					return
+				}
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			// the current file was drained above, so switching files loses no line
+			reopened, err := t.check()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if reopened {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+type tailer struct {
+	name    string
+	f       *os.File
+	offset  int64  // bytes consumed from f
+	partial []byte // start of a line whose newline is not written yet
+	line    int    // lines read from the current file since it was opened
+}
+
+func (t *tailer) open(atEnd bool) error {
+	f, err := os.Open(t.name)
+	if err != nil {
+		return err
+	}
+	t.f, t.offset, t.partial, t.line = f, 0, nil, 0
+	if atEnd {
+		if t.offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// read returns the complete lines appended since the last call.
+func (t *tailer) read() ([][]byte, error) {
+	buf := make([]byte, 32*1024)
+	var lines [][]byte
+	for {
+		n, err := t.f.Read(buf)
+		t.offset += int64(n)
+		data := append(t.partial, buf[:n]...)
+		for {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			lines = append(lines, bytes.TrimRight(data[:i], "\r"))
+			data = data[i+1:]
+		}
+		t.partial = append([]byte(nil), data...)
+		if errors.Is(err, io.EOF) || n == 0 {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+// check rewinds the file if it was truncated, and reports whether it was
+// rewound or replaced by a new file, which is then opened.
+func (t *tailer) check() (bool, error) {
+	fi, err := os.Stat(t.name)
+	if errors.Is(err, os.ErrNotExist) {
+		// rotated and not recreated yet
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	cur, err := t.f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if !os.SameFile(fi, cur) {
+		t.f.Close()
+		return true, t.open(false)
+	}
+	if fi.Size() < t.offset {
+		t.offset, t.partial, t.line = 0, nil, 0
+		_, err = t.f.Seek(0, io.SeekStart)
+		return true, err
+	}
+	return false, nil
+}
@@ -0,0 +1,113 @@
+package jsonl
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"strings"
+)
+
+// Writer encodes values of type T one per line, through a buffer.
+// Call Flush or Close to write out the buffered lines.
+type Writer[T any] struct {
+	buf    *bufio.Writer
+	enc    *json.Encoder
+	gz     *gzip.Writer // nil unless compressing
+	closer io.Closer    // the file opened by Create
+	n      int
+}
+
+// NewWriter creates a Writer to w.
+func NewWriter[T any](w io.Writer) *Writer[T] {
+	buf := bufio.NewWriter(w)
+	return &Writer[T]{buf: buf, enc: newEncoder(buf)}
+}
+
+// NewGzipWriter creates a Writer compressing to w with the given gzip level,
+// e.g. gzip.DefaultCompression.
+func NewGzipWriter[T any](w io.Writer, level int) (*Writer[T], error) {
+	gz, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(gz)
+	return &Writer[T]{buf: buf, enc: newEncoder(buf), gz: gz}, nil
+}
+
+// Create creates or truncates the file name and returns a Writer to it, compressing
+// if name ends with ".gz". Close closes the file.
+func Create[T any](name string) (*Writer[T], error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := NewWriter[T](f)
+	if strings.HasSuffix(name, ".gz") {
+		if w, err = NewGzipWriter[T](f, gzip.DefaultCompression); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	w.closer = f
+	return w, nil
+}
+
+func newEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc
+}
+
+// Write encodes v on one line.
+func (w *Writer[T]) Write(v T) error {
+	// Encode appends the newline
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	w.n++
+	return nil
+}
+
+// WriteAll writes the values of seq and stops at the first error.
+func (w *Writer[T]) WriteAll(seq iter.Seq[T]) error {
+	for v := range seq {
+		if err := w.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the number of lines written.
+func (w *Writer[T]) Count() int {
+	return w.n
+}
+
+// Flush writes out the buffered lines, and flushes the gzip stream if compressing,
+// so that readers of the underlying writer see every complete line.
+func (w *Writer[T]) Flush() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	if w.gz != nil {
+		return w.gz.Flush()
+	}
+	return nil
+}
+
+// Close flushes the buffered lines and ends the gzip stream if compressing.
+// It closes the underlying writer only if it was opened by Create.
+func (w *Writer[T]) Close() error {
+	err := w.buf.Flush()
+	if w.gz != nil {
+		err = errors.Join(err, w.gz.Close())
+	}
+	if w.closer != nil {
+		err = errors.Join(err, w.closer.Close())
+	}
+	return err
+}
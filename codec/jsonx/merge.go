@@ -0,0 +1,68 @@
+package jsonx
+
+import "encoding/json"
+
+// MergePatch applies an RFC 7386 merge patch to a copy of target and returns it:
+// members of patch objects replace those of target, recursively, and null members
+// delete them. A patch that is not an object replaces target as a whole.
+func MergePatch(target, patch any) any {
+	return mergePatch(normalize(target), normalize(patch))
+}
+
+func mergePatch(target, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]any)
+	if !ok {
+		tm = make(map[string]any, len(pm))
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+		} else {
+			tm[k] = mergePatch(tm[k], v)
+		}
+	}
+	return tm
+}
+
+// CreateMergePatch returns the RFC 7386 merge patch turning a into b.
+// Merge patches cannot express setting a member to null or changing part of an array,
+// so those are encoded as deletions and whole array replacements.
+func CreateMergePatch(a, b any) any {
+	return createMergePatch(normalize(a), normalize(b))
+}
+
+func createMergePatch(a, b any) any {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		return b
+	}
+	p := make(map[string]any)
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			p[k] = nil
+		}
+	}
+	for k, v := range bm {
+		if w, ok := am[k]; !ok || !equal(v, w) {
+			p[k] = createMergePatch(w, v)
+		}
+	}
+	return p
+}
+
+// MergePatchJSON applies the JSON merge patch to the JSON document doc.
+func MergePatchJSON(doc, patch []byte) ([]byte, error) {
+	var d, p any
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(d, p))
+}
@@ -0,0 +1,238 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed is returned when a test operation of a Patch does not match.
+var ErrTestFailed = errors.New("jsonx: test operation failed")
+
+// Operation is one operation of an RFC 6902 JSON Patch.
+type Operation struct {
+	Op    string `json:"op"` // add, remove, replace, move, copy or test
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"` // source of move and copy
+	Value any    `json:"value,omitempty"`
+}
+
+// MarshalJSON writes value for the operations that take one, even when it is null.
+func (op Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	switch op.Op {
+	case "add", "replace", "test":
+		return json.Marshal(struct {
+			plain
+			Value any `json:"value"`
+		}{plain(op), op.Value})
+	}
+	return json.Marshal(plain(op))
+}
+
+// Patch is an RFC 6902 JSON Patch.
+type Patch []Operation
+
+// Apply applies the patch to a copy of doc and returns it. doc is left untouched,
+// and nothing is returned if an operation fails.
+func (p Patch) Apply(doc any) (any, error) {
+	doc = normalize(doc)
+	for i, op := range p {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("jsonx: operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func (op Operation) apply(doc any) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		return add(doc, path, normalize(op.Value))
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		if _, err := resolve(doc, path); err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, normalize(op.Value))
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		if op.Op == "move" {
+			if len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+				return nil, errors.New("cannot move a value into itself")
+			}
+			if doc, v, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if v, err = resolve(doc, from); err != nil {
+				return nil, err
+			}
+			v = normalize(v)
+		}
+		return add(doc, path, v)
+	case "test":
+		v, err := resolve(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(v, normalize(op.Value)) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+func add(doc any, path []string, v any) (any, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	return walk(doc, path, func(parent any, last string) (any, error) {
+		switch x := parent.(type) {
+		case map[string]any:
+			x[last] = v
+			return x, nil
+		case []any:
+			i, err := arrayIndex(last, len(x), true)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(x, i, v), nil
+		}
+		return nil, fmt.Errorf("%w: %q in a %T", ErrNotFound, last, parent)
+	})
+}
+
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	var removed any
+	doc, err := walk(doc, path, func(parent any, last string) (any, error) {
+		switch x := parent.(type) {
+		case map[string]any:
+			v, ok := x[last]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q", ErrNotFound, last)
+			}
+			removed = v
+			delete(x, last)
+			return x, nil
+		case []any:
+			i, err := arrayIndex(last, len(x), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = x[i]
+			return slices.Delete(x, i, i+1), nil
+		}
+		return nil, fmt.Errorf("%w: %q in a %T", ErrNotFound, last, parent)
+	})
+	return doc, removed, err
+}
+
+// ApplyPatch decodes an RFC 6902 patch and applies it to the JSON document doc.
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	var p Patch
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	v, err := p.Apply(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Diff returns a patch turning a into b. Objects are compared member by member in key order;
+// arrays element by element, with the extra elements added or removed at the end.
+func Diff(a, b any) Patch {
+	var p Patch
+	diff(&p, "", normalize(a), normalize(b))
+	return p
+}
+
+func diff(p *Patch, path string, a, b any) {
+	if equal(a, b) {
+		return
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(x) {
+			kp := path + "/" + escapeToken(k)
+			if w, ok := y[k]; ok {
+				diff(p, kp, x[k], w)
+			} else {
+				*p = append(*p, Operation{Op: "remove", Path: kp})
+			}
+		}
+		for _, k := range sortedKeys(y) {
+			if _, ok := x[k]; !ok {
+				*p = append(*p, Operation{Op: "add", Path: path + "/" + escapeToken(k), Value: y[k]})
+			}
+		}
+		return
+	case []any:
+		y, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < min(len(x), len(y)); i++ {
+			diff(p, path+"/"+strconv.Itoa(i), x[i], y[i])
+		}
+		for i := len(x); i < len(y); i++ {
+			*p = append(*p, Operation{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: y[i]})
+		}
+		for i := len(x) - 1; i >= len(y); i-- {
+			*p = append(*p, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		return
+	}
+	*p = append(*p, Operation{Op: "replace", Path: path, Value: b})
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// String returns the patch as JSON.
+func (p Patch) String() string {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(p); err != nil {
+		return fmt.Sprintf("jsonx: %v", err)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,116 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	for _, tt := range []struct {
+		doc, patch, want string
+		err              bool
+	}{
+		{`{"a":1}`, `[{"op":"add","path":"/b","value":[1,2]}]`, `{"a":1,"b":[1,2]}`, false},
+		{`{"a":[1,2]}`, `[{"op":"add","path":"/a/1","value":9}]`, `{"a":[1,9,2]}`, false},
+		{`{"a":[1,2]}`, `[{"op":"add","path":"/a/-","value":3}]`, `{"a":[1,2,3]}`, false},
+		{`{"a":[1,2]}`, `[{"op":"add","path":"/a/3","value":3}]`, ``, true},
+		{`{"a":{"b":1},"c":2}`, `[{"op":"remove","path":"/a/b"}]`, `{"a":{},"c":2}`, false},
+		{`{"a":[1,2,3]}`, `[{"op":"remove","path":"/a/0"}]`, `{"a":[2,3]}`, false},
+		{`{"a":1}`, `[{"op":"remove","path":"/b"}]`, ``, true},
+		{`{"a":1}`, `[{"op":"replace","path":"/a","value":null}]`, `{"a":null}`, false},
+		{`{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`, ``, true},
+		{`{"a":{"b":1}}`, `[{"op":"move","from":"/a/b","path":"/c"}]`, `{"a":{},"c":1}`, false},
+		{`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`, ``, true},
+		{`{"a":[1]}`, `[{"op":"copy","from":"/a","path":"/b"},{"op":"add","path":"/b/-","value":2}]`, `{"a":[1],"b":[1,2]}`, false},
+		{`{"a/b":{"~":1}}`, `[{"op":"test","path":"/a~1b/~0","value":1.0}]`, `{"a/b":{"~":1}}`, false},
+		{`{"a":1}`, `[{"op":"test","path":"/a","value":2}]`, ``, true},
+		{`{"a":1}`, `[{"op":"add","path":"","value":[1]}]`, `[1]`, false},
+		{`{"a":1}`, `[{"op":"frob","path":"/a"}]`, ``, true},
+	} {
+		got, err := ApplyPatch([]byte(tt.doc), []byte(tt.patch))
+		if tt.err {
+			if err == nil {
+				t.Errorf("ApplyPatch(%s, %s) = %s, want an error", tt.doc, tt.patch, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ApplyPatch(%s, %s) error = %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if !jsonEqual(t, got, tt.want) {
+			t.Errorf("ApplyPatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestPatch_Atomic(t *testing.T) {
+	doc := map[string]any{"a": 1.0}
+	_, err := Patch{
+		{Op: "add", Path: "/b", Value: 2},
+		{Op: "test", Path: "/a", Value: 2},
+	}.Apply(doc)
+	if !errors.Is(err, ErrTestFailed) {
+		t.Errorf("Apply() err = %v, want ErrTestFailed", err)
+	}
+	if len(doc) != 1 {
+		t.Errorf("Apply() modified its input: %v", doc)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := decode(t, `{"a":1,"b":{"c":[1,2,3],"d":"x"},"e":true,"k/~":0}`)
+	b := decode(t, `{"a":2,"b":{"c":[1,5],"f":null},"g":[],"k/~":0}`)
+	p := Diff(a, b)
+	got, err := p.Apply(a)
+	if err != nil {
+		t.Fatalf("Apply(Diff()) error = %v, patch %s", err, p)
+	}
+	if !Equal(got, b) {
+		t.Errorf("Apply(Diff()) = %v, want %v, patch %s", got, b, p)
+	}
+	if len(Diff(a, a)) != 0 {
+		t.Error("Diff() of equal documents is not empty")
+	}
+	bs, _ := json.Marshal(Patch{{Op: "add", Path: "/x"}, {Op: "remove", Path: "/y"}})
+	if want := `[{"op":"add","path":"/x","value":null},{"op":"remove","path":"/y"}]`; string(bs) != want {
+		t.Errorf("Marshal(Patch) = %s, want %s", bs, want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	for _, tt := range []struct{ doc, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `{"a":"b"}`, `{"a":"b"}`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+	} {
+		got, err := MergePatchJSON([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jsonEqual(t, got, tt.want) {
+			t.Errorf("MergePatchJSON(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+
+	a := decode(t, `{"a":1,"b":{"c":2,"d":3},"e":[1]}`)
+	b := decode(t, `{"a":1,"b":{"c":4},"e":[1,2],"f":"x"}`)
+	p := CreateMergePatch(a, b)
+	if !Equal(p, decode(t, `{"b":{"c":4,"d":null},"e":[1,2],"f":"x"}`)) {
+		t.Errorf("CreateMergePatch() = %v", p)
+	}
+	if got := MergePatch(a, p); !Equal(got, b) {
+		t.Errorf("MergePatch(CreateMergePatch()) = %v, want %v", got, b)
+	}
+}
+
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	return Equal(decode(t, string(got)), decode(t, want))
+}
@@ -0,0 +1,290 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when a path or pointer matches nothing.
+	ErrNotFound = errors.New("jsonx: not found")
+	// ErrType is returned when a matched value does not convert to the requested type.
+	ErrType = errors.New("jsonx: type mismatch")
+)
+
+type segKind int
+
+const (
+	segName segKind = iota
+	segIndex
+	segWildcard
+	segSlice
+)
+
+type segment struct {
+	kind      segKind
+	name      string
+	index     int
+	start     *int // slice bounds, nil when omitted
+	end       *int
+	recursive bool // preceded by ..
+}
+
+// Path is a compiled JSONPath expression. It supports the root $, child names
+// (.name or ['name']), array indexes ([0], negative from the end), wildcards
+// (.* or [*]), slices ([1:3], [:-1]) and recursive descent (..name).
+// The leading $ is optional, so "data[0].instId" is the same as "$.data[0].instId".
+type Path struct {
+	expr string
+	segs []segment
+}
+
+// Compile parses a JSONPath expression.
+func Compile(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	s := strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(s, "$"); ok {
+		s = rest
+	} else if s != "" && s[0] != '.' && s[0] != '[' {
+		s = "." + s
+	}
+	for s != "" {
+		var seg segment
+		switch {
+		case strings.HasPrefix(s, ".."):
+			seg.recursive = true
+			s = s[2:]
+			if strings.HasPrefix(s, "[") {
+				break
+			}
+			fallthrough
+		case s[0] == '.':
+			s = strings.TrimPrefix(s, ".")
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("jsonx: empty name in path %q", expr)
+			}
+			if s[:end] == "*" {
+				seg.kind = segWildcard
+			} else {
+				seg.kind, seg.name = segName, s[:end]
+			}
+			s = s[end:]
+			p.segs = append(p.segs, seg)
+			continue
+		case s[0] != '[':
+			return nil, fmt.Errorf("jsonx: unexpected %q in path %q", s[0], expr)
+		}
+		rest, err := parseBracket(s, &seg)
+		if err != nil {
+			return nil, fmt.Errorf("jsonx: %v in path %q", err, expr)
+		}
+		s = rest
+		p.segs = append(p.segs, seg)
+	}
+	return p, nil
+}
+
+// MustCompile is like Compile but panics on error.
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// parseBracket parses a [...] selector at the start of s and returns the rest of s.
+func parseBracket(s string, seg *segment) (string, error) {
+	s = s[1:]
+	if len(s) > 0 && (s[0] == '\'' || s[0] == '"') {
+		quote := s[0]
+		end := strings.IndexByte(s[1:], quote)
+		if end < 0 || !strings.HasPrefix(s[end+2:], "]") {
+			return "", errors.New("unterminated quoted name")
+		}
+		seg.kind, seg.name = segName, s[1:end+1]
+		return s[end+3:], nil
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return "", errors.New("missing ]")
+	}
+	inner, rest := strings.TrimSpace(s[:end]), s[end+1:]
+	if inner == "*" {
+		seg.kind = segWildcard
+		return rest, nil
+	}
+	if lo, hi, ok := strings.Cut(inner, ":"); ok {
+		seg.kind = segSlice
+		for _, b := range []struct {
+			s   string
+			dst **int
+		}{{lo, &seg.start}, {hi, &seg.end}} {
+			if b.s = strings.TrimSpace(b.s); b.s == "" {
+				continue
+			}
+			n, err := strconv.Atoi(b.s)
+			if err != nil {
+				return "", fmt.Errorf("invalid slice bound %q", b.s)
+			}
+			*b.dst = &n
+		}
+		return rest, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return "", fmt.Errorf("invalid index %q", inner)
+	}
+	seg.kind, seg.index = segIndex, n
+	return rest, nil
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Query returns all the values matched by p in doc, in document order.
+// Object members are visited in key order, so the result is deterministic.
+func (p *Path) Query(doc any) []any {
+	nodes := []any{normalizeShallow(doc)}
+	for _, seg := range p.segs {
+		var next []any
+		for _, n := range nodes {
+			if seg.recursive {
+				descend(n, func(d any) { next = seg.apply(d, next) })
+			} else {
+				next = seg.apply(n, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// normalizeShallow lets the queries walk named map and slice types such as types.M.
+func normalizeShallow(v any) any {
+	switch v.(type) {
+	case map[string]any, []any:
+		return v
+	}
+	return normalize(v)
+}
+
+func (seg *segment) apply(n any, out []any) []any {
+	switch seg.kind {
+	case segName:
+		if m, ok := n.(map[string]any); ok {
+			if v, ok := m[seg.name]; ok {
+				out = append(out, normalizeShallow(v))
+			}
+		}
+	case segIndex:
+		if a, ok := n.([]any); ok {
+			i := seg.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				out = append(out, normalizeShallow(a[i]))
+			}
+		}
+	case segWildcard:
+		for _, v := range children(n) {
+			out = append(out, v)
+		}
+	case segSlice:
+		if a, ok := n.([]any); ok {
+			lo, hi := bound(seg.start, 0, len(a)), bound(seg.end, len(a), len(a))
+			for i := lo; i < hi; i++ {
+				out = append(out, normalizeShallow(a[i]))
+			}
+		}
+	}
+	return out
+}
+
+func bound(b *int, def, n int) int {
+	if b == nil {
+		return def
+	}
+	i := *b
+	if i < 0 {
+		i += n
+	}
+	return min(max(i, 0), n)
+}
+
+// children returns the members of an object in key order, or the elements of an array.
+func children(n any) []any {
+	switch x := n.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		rv := make([]any, len(keys))
+		for i, k := range keys {
+			rv[i] = normalizeShallow(x[k])
+		}
+		return rv
+	case []any:
+		rv := make([]any, len(x))
+		for i, v := range x {
+			rv[i] = normalizeShallow(v)
+		}
+		return rv
+	}
+	return nil
+}
+
+// descend calls f for n and all its descendants, parents first.
+func descend(n any, f func(any)) {
+	f(n)
+	for _, c := range children(n) {
+		descend(c, f)
+	}
+}
+
+// Query returns all the values matched by the JSONPath expr in doc.
+func Query(doc any, expr string) ([]any, error) {
+	p, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Query(doc), nil
+}
+
+// Get returns the first value matched by the JSONPath expr in doc, converted to T with As.
+// It returns ErrNotFound if nothing matches and ErrType if the value does not convert.
+func Get[T any](doc any, expr string) (T, error) {
+	var zero T
+	p, err := Compile(expr)
+	if err != nil {
+		return zero, err
+	}
+	vs := p.Query(doc)
+	if len(vs) == 0 {
+		return zero, fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	v, ok := As[T](vs[0])
+	if !ok {
+		return zero, fmt.Errorf("%w: %s is %T, not %T", ErrType, expr, vs[0], zero)
+	}
+	return v, nil
+}
+
+// GetOr is like Get but returns defval on any error, including an invalid expr.
+func GetOr[T any](doc any, expr string, defval T) T {
+	if v, err := Get[T](doc, expr); err == nil {
+		return v
+	}
+	return defval
+}
@@ -0,0 +1,116 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type M map[string]any
+
+const book = `{
+	"code": "0",
+	"data": [
+		{"instId": "BTC-USDT", "px": "65000", "sz": 1.5, "tags": ["spot"]},
+		{"instId": "ETH-USDT", "px": "3500", "sz": 2, "tags": []},
+		{"instId": "SOL-USDT", "px": "150", "sz": 10, "tags": ["spot", "hot"]}
+	],
+	"meta": {"a.b": {"instId": "nested"}}
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestQuery(t *testing.T) {
+	doc := decode(t, book)
+	for _, tt := range []struct {
+		path string
+		want []any
+	}{
+		{"$.code", []any{"0"}},
+		{"data[0].instId", []any{"BTC-USDT"}},
+		{"$.data[-1].px", []any{"150"}},
+		{"$.data[*].sz", []any{1.5, 2.0, 10.0}},
+		{"$.data[1:].instId", []any{"ETH-USDT", "SOL-USDT"}},
+		{"$.data[:-2].instId", []any{"BTC-USDT"}},
+		{"$['meta']['a.b'].instId", []any{"nested"}},
+		{"$..instId", []any{"BTC-USDT", "ETH-USDT", "SOL-USDT", "nested"}},
+		{"$.data[2].tags.*", []any{"spot", "hot"}},
+		{"$.missing", nil},
+		{"$.data[7]", nil},
+	} {
+		got, err := Query(doc, tt.path)
+		if err != nil {
+			t.Errorf("Query(%s) error = %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Query(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"$.", "$[", "$['x", "$[a]", "$x"} {
+		if _, err := Compile(bad); err == nil {
+			t.Errorf("Compile(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc := M{"data": []M{{"sz": 2.0, "ok": true, "m": map[string]any{"k": 1}}}}
+	if n, err := Get[int](doc, "data[0].sz"); err != nil || n != 2 {
+		t.Errorf("Get[int] = %d, %v", n, err)
+	}
+	if _, err := Get[string](doc, "data[0].sz"); !errors.Is(err, ErrType) {
+		t.Errorf("Get[string] of a number err = %v, want ErrType", err)
+	}
+	if _, err := Get[bool](doc, "data[0].nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing member err = %v, want ErrNotFound", err)
+	}
+	if m, err := Get[M](doc, "data[0].m"); err != nil || m["k"] != 1 {
+		t.Errorf("Get[M] = %v, %v", m, err)
+	}
+	if got := GetOr(doc, "data[0].ok", false); !got {
+		t.Error("GetOr(data[0].ok) = false")
+	}
+}
+
+func TestAs(t *testing.T) {
+	if v, ok := As[int8](300.0); ok {
+		t.Errorf("As[int8](300) = %d, true", v)
+	}
+	if v, ok := As[uint](json.Number("42")); !ok || v != 42 {
+		t.Errorf("As[uint](42) = %d, %v", v, ok)
+	}
+	if _, ok := As[int](1.5); ok {
+		t.Error("As[int](1.5) succeeded")
+	}
+	if _, ok := As[string](65); ok {
+		t.Error("As[string](65) succeeded")
+	}
+	if v, ok := As[int64](json.Number("9007199254740993")); !ok || v != 1<<53+1 {
+		t.Errorf("As[int64](2^53+1) = %d, %v", v, ok)
+	}
+	if v, ok := As[int64](uint64(1<<53 + 1)); !ok || v != 1<<53+1 {
+		t.Errorf("As[int64](uint64(2^53+1)) = %d, %v", v, ok)
+	}
+	if v, ok := As[uint64](json.Number("18446744073709551615")); !ok || v != math.MaxUint64 {
+		t.Errorf("As[uint64](MaxUint64) = %d, %v", v, ok)
+	}
+	if v, ok := As[int](json.Number("1e3")); !ok || v != 1000 {
+		t.Errorf("As[int](1e3) = %d, %v", v, ok)
+	}
+	if v, ok := As[int64](json.Number("9007199254740993.0")); ok {
+		t.Errorf("As[int64](9007199254740993.0) = %d, true", v)
+	}
+	if v, ok := As[uint](-1); ok {
+		t.Errorf("As[uint](-1) = %d, true", v)
+	}
+}
@@ -0,0 +1,108 @@
+package jsonx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer splits an RFC 6901 JSON Pointer such as "/data/0/instId" into
+// unescaped tokens. The empty pointer refers to the whole document.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("jsonx: invalid JSON pointer %q", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// escapeToken escapes a key for use in a JSON Pointer.
+func escapeToken(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// arrayIndex parses an array index token. end allows the index one past the last element.
+func arrayIndex(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && token[0] == '0') {
+		return 0, fmt.Errorf("jsonx: invalid array index %q", token)
+	}
+	if i > n || (i == n && !end) {
+		return 0, fmt.Errorf("%w: index %d out of %d elements", ErrNotFound, i, n)
+	}
+	return i, nil
+}
+
+// Resolve returns the value at the JSON Pointer ptr in doc.
+func Resolve(doc any, ptr string) (any, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(normalizeShallow(doc), tokens)
+}
+
+func resolve(n any, tokens []string) (any, error) {
+	for _, t := range tokens {
+		switch x := n.(type) {
+		case map[string]any:
+			v, ok := x[t]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q", ErrNotFound, t)
+			}
+			n = normalizeShallow(v)
+		case []any:
+			i, err := arrayIndex(t, len(x), false)
+			if err != nil {
+				return nil, err
+			}
+			n = normalizeShallow(x[i])
+		default:
+			return nil, fmt.Errorf("%w: %q in a %T", ErrNotFound, t, n)
+		}
+	}
+	return n, nil
+}
+
+// walk finds the parent of the last token and replaces it with the result of fn,
+// writing the new containers back into their parents since appending to or deleting
+// from a []any returns a new slice. n must be normalized.
+func walk(n any, tokens []string, fn func(parent any, last string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(n, tokens[0])
+	}
+	switch x := n.(type) {
+	case map[string]any:
+		c, ok := x[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q", ErrNotFound, tokens[0])
+		}
+		c, err := walk(c, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		x[tokens[0]] = c
+		return x, nil
+	case []any:
+		i, err := arrayIndex(tokens[0], len(x), false)
+		if err != nil {
+			return nil, err
+		}
+		c, err := walk(x[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		x[i] = c
+		return x, nil
+	}
+	return nil, fmt.Errorf("%w: %q in a %T", ErrNotFound, tokens[0], n)
+}
@@ -0,0 +1,215 @@
+// Package jsonx queries and patches decoded JSON documents: values made of
+// map[string]any, []any, strings, numbers, booleans and nil, such as the result
+// of json.Unmarshal into an any or reqs.Resp.JSONMap.
+package jsonx
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// normalize returns a deep copy of v where maps with string keys, such as types.M,
+// become map[string]any and slices other than []byte become []any.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case nil, string, bool, float64, json.Number:
+		return v
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for k, e := range x {
+			m[k] = normalize(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(x))
+		for i, e := range x {
+			s[i] = normalize(e)
+		}
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		m := make(map[string]any, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			m[it.Key().String()] = normalize(it.Value().Interface())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		s := make([]any, rv.Len())
+		for i := range s {
+			s[i] = normalize(rv.Index(i).Interface())
+		}
+		return s
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	}
+	return v
+}
+
+// Equal reports whether two JSON values are equal. Numbers compare by value
+// whatever their Go type, so 1, int64(1) and 1.0 are equal.
+func Equal(a, b any) bool {
+	a, b = normalize(a), normalize(b)
+	return equal(a, b)
+}
+
+func equal(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// toInt64 returns v as an int64 if it is an integer number, without going through
+// float64 for integers and json.Number.
+func toInt64(v any) (int64, bool) {
+	if n, ok := v.(json.Number); ok {
+		if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return i, true
+		}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		return int64(u), u <= math.MaxInt64
+	}
+	f, ok := floatInt(v)
+	if !ok || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// toUint64 is like toInt64 for a uint64.
+func toUint64(v any) (uint64, bool) {
+	if n, ok := v.(json.Number); ok {
+		if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return u, true
+		}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		return uint64(i), i >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), true
+	}
+	f, ok := floatInt(v)
+	if !ok || f < 0 || f >= math.MaxUint64 {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+// floatInt returns the float value of v if it is an integer. A json.Number such as
+// "1e3" or "2.0" qualifies only below 2^53, from which its float may be rounded.
+func floatInt(v any) (float64, bool) {
+	f, ok := toFloat(v)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	if _, ok := v.(json.Number); ok && math.Abs(f) >= 1<<53 {
+		return 0, false
+	}
+	return f, true
+}
+
+// As converts a JSON value to T. Besides plain type assertions, numbers convert
+// between numeric types when no precision is lost, e.g. float64(3) to int,
+// and values convert to named types of the same kind, e.g. map[string]any to types.M.
+func As[T any](v any) (T, bool) {
+	if t, ok := v.(T); ok {
+		return t, true
+	}
+	var zero T
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return zero, false
+	}
+	rt := reflect.TypeFor[T]()
+	out := reflect.New(rt).Elem()
+	if f, ok := toFloat(v); ok {
+		switch out.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, ok := toInt64(v)
+			if !ok || out.OverflowInt(i) {
+				return zero, false
+			}
+			out.SetInt(i)
+			return out.Interface().(T), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u, ok := toUint64(v)
+			if !ok || out.OverflowUint(u) {
+				return zero, false
+			}
+			out.SetUint(u)
+			return out.Interface().(T), true
+		case reflect.Float32, reflect.Float64:
+			out.SetFloat(f)
+			return out.Interface().(T), true
+		}
+	}
+	if rv.Kind() == rt.Kind() && rv.Type().ConvertibleTo(rt) {
+		return rv.Convert(rt).Interface().(T), true
+	}
+	return zero, false
+}
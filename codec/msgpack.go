@@ -0,0 +1,347 @@
+package codec
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenyan/wheels/internal/reflectx"
+)
+
+// MsgPack encodes values as MessagePack (https://msgpack.org).
+//
+// Struct fields are named by their msgpack tag, falling back to the json tag and then
+// to the field name, and support the "-" name and the omitempty option. Embedded structs
+// without a name are flattened. time.Time uses the timestamp extension, and types
+// implementing encoding.TextMarshaler are encoded as strings. When decoding into an
+// interface, maps become map[string]any (or map[any]any for non-string keys), arrays
+// []any, integers int64 (uint64 above math.MaxInt64) and floats float64.
+var MsgPack Codec = msgpackCodec{}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	e := &mpEncoder{}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("msgpack: Unmarshal needs a non-nil pointer, got %T", v)
+	}
+	d := &mpDecoder{data: data}
+	if err := d.decode(rv.Elem(), 0); err != nil {
+		return err
+	}
+	if d.pos != len(d.data) {
+		return fmt.Errorf("msgpack: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return nil
+}
+
+func (msgpackCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+const mpTimestampExt = -1
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// mpField is a struct field as seen by msgpack.
+type mpField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var mpFieldCache sync.Map // reflect.Type -> []mpField
+
+func mpFields(t reflect.Type) []mpField {
+	if fs, ok := mpFieldCache.Load(t); ok {
+		return fs.([]mpField)
+	}
+	var fields []mpField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("msgpack")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for _, f := range mpFields(ft) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, mpField{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+	mpFieldCache.Store(t, fields)
+	return fields
+}
+
+type mpEncoder struct {
+	buf []byte
+}
+
+func (e *mpEncoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, 0xc0)
+		return nil
+	}
+	if k := v.Kind(); k == reflect.Pointer || k == reflect.Interface {
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		return e.encode(v.Elem())
+	}
+	t := v.Type()
+	if t == timeType {
+		e.encodeTime(v.Interface().(time.Time))
+		return nil
+	}
+	if t.Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		e.encodeStr(string(text))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.encodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.encodeUint(v.Uint())
+	case reflect.Float32:
+		e.buf = append(e.buf, 0xca)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		e.buf = append(e.buf, 0xcb)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.encodeStr(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			e.encodeBin(v.Bytes())
+			return nil
+		}
+		return e.encodeArray(v)
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			bs := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(bs), v)
+			e.encodeBin(bs)
+			return nil
+		}
+		return e.encodeArray(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", t)
+	}
+	return nil
+}
+
+func (e *mpEncoder) encodeInt(n int64) {
+	switch {
+	case n >= 0:
+		e.encodeUint(uint64(n))
+	case n >= -32:
+		e.buf = append(e.buf, byte(n))
+	case n >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xd2), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xd3), uint64(n))
+	}
+}
+
+func (e *mpEncoder) encodeUint(n uint64) {
+	switch {
+	case n <= math.MaxInt8:
+		e.buf = append(e.buf, byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xce), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xcf), n)
+	}
+}
+
+// encodeLen writes the header of a str or bin of length n. fix is the fixed-size format,
+// or 0 if there is none, and base the 8-bit format, which the 16 and 32-bit formats follow.
+func (e *mpEncoder) encodeLen(n int, fix byte, fixMax int, base byte) {
+	switch {
+	case fix != 0 && n <= fixMax:
+		e.buf = append(e.buf, fix|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, base, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, base+1), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, base+2), uint32(n))
+	}
+}
+
+func (e *mpEncoder) encodeStr(s string) {
+	e.encodeLen(len(s), 0xa0, 31, 0xd9)
+	e.buf = append(e.buf, s...)
+}
+
+func (e *mpEncoder) encodeBin(bs []byte) {
+	e.encodeLen(len(bs), 0, 0, 0xc4)
+	e.buf = append(e.buf, bs...)
+}
+
+func (e *mpEncoder) encodeArrayLen(n int) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xdc), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdd), uint32(n))
+	}
+}
+
+func (e *mpEncoder) encodeMapLen(n int) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xde), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdf), uint32(n))
+	}
+}
+
+func (e *mpEncoder) encodeArray(v reflect.Value) error {
+	e.encodeArrayLen(v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeMap writes the entries sorted by key when the keys are strings or numbers,
+// so that equal maps have equal encodings.
+func (e *mpEncoder) encodeMap(v reflect.Value) error {
+	keys := v.MapKeys()
+	switch v.Type().Key().Kind() {
+	case reflect.String:
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return cmp.Compare(a.Int(), b.Int()) })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return cmp.Compare(a.Uint(), b.Uint()) })
+	}
+	e.encodeMapLen(len(keys))
+	for _, k := range keys {
+		if err := e.encode(k); err != nil {
+			return err
+		}
+		if err := e.encode(v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *mpEncoder) encodeStruct(v reflect.Value) error {
+	type kv struct {
+		name  string
+		value reflect.Value
+	}
+	var fields []kv
+	for _, f := range mpFields(v.Type()) {
+		fv, ok := reflectx.FieldByIndex(v, f.index, false)
+		if !ok || (f.omitEmpty && fv.IsZero()) {
+			continue
+		}
+		fields = append(fields, kv{f.name, fv})
+	}
+	e.encodeMapLen(len(fields))
+	for _, f := range fields {
+		e.encodeStr(f.name)
+		if err := e.encode(f.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeTime writes the timestamp extension in its 32, 64 or 96-bit form.
+func (e *mpEncoder) encodeTime(t time.Time) {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec>>32 == 0 && nsec == 0:
+		e.buf = append(e.buf, 0xd6, 0xff)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(sec))
+	case sec>>34 == 0:
+		e.buf = append(e.buf, 0xd7, 0xff)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, nsec<<34|uint64(sec))
+	default:
+		e.buf = append(e.buf, 0xc7, 12, 0xff)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(nsec))
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(sec))
+	}
+}
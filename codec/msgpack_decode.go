@@ -0,0 +1,485 @@
+package codec
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/chenyan/wheels/internal/reflectx"
+)
+
+var errShortData = errors.New("msgpack: unexpected end of data")
+
+// mpMaxDepth bounds the nesting of arrays and maps, so that hostile input cannot exhaust the stack.
+const mpMaxDepth = 10000
+
+// mpFormat is the family of a MessagePack value.
+type mpFormat int
+
+const (
+	mpNil mpFormat = iota
+	mpBool
+	mpInt   // n holds the int64 bits
+	mpUint  // n holds the uint64
+	mpFloat // n holds the float64 bits
+	mpStr   // n is the length
+	mpBin
+	mpArray
+	mpMap
+	mpExt // n is the length, ext the type
+)
+
+func (f mpFormat) String() string {
+	return [...]string{"nil", "bool", "int", "uint", "float", "str", "bin", "array", "map", "ext"}[f]
+}
+
+type mpDecoder struct {
+	data []byte
+	pos  int
+	ext  int8 // type of the last ext header
+}
+
+func (d *mpDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errShortData
+	}
+	bs := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return bs, nil
+}
+
+func (d *mpDecoder) uint(size int) (uint64, error) {
+	bs, err := d.read(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(bs[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(bs)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(bs)), nil
+	}
+	return binary.BigEndian.Uint64(bs), nil
+}
+
+// header reads the header of the next value. The payload of str, bin and ext values follows.
+func (d *mpDecoder) header() (f mpFormat, n uint64, err error) {
+	bs, err := d.read(1)
+	if err != nil {
+		return 0, 0, err
+	}
+	b := bs[0]
+	switch {
+	case b <= 0x7f:
+		return mpUint, uint64(b), nil
+	case b >= 0xe0:
+		return mpInt, uint64(int64(int8(b))), nil
+	case b&0xf0 == 0x80:
+		return mpMap, uint64(b & 0x0f), nil
+	case b&0xf0 == 0x90:
+		return mpArray, uint64(b & 0x0f), nil
+	case b&0xe0 == 0xa0:
+		return mpStr, uint64(b & 0x1f), nil
+	}
+	switch b {
+	case 0xc0:
+		return mpNil, 0, nil
+	case 0xc2, 0xc3:
+		return mpBool, uint64(b & 1), nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err = d.uint(1 << (b - 0xc4))
+		return mpBin, n, err
+	case 0xc7, 0xc8, 0xc9:
+		if n, err = d.uint(1 << (b - 0xc7)); err != nil {
+			return 0, 0, err
+		}
+		t, err := d.uint(1)
+		d.ext = int8(t)
+		return mpExt, n, err
+	case 0xca:
+		n, err = d.uint(4)
+		return mpFloat, math.Float64bits(float64(math.Float32frombits(uint32(n)))), err
+	case 0xcb:
+		n, err = d.uint(8)
+		return mpFloat, n, err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err = d.uint(1 << (b - 0xcc))
+		return mpUint, n, err
+	case 0xd0:
+		n, err = d.uint(1)
+		return mpInt, uint64(int64(int8(n))), err
+	case 0xd1:
+		n, err = d.uint(2)
+		return mpInt, uint64(int64(int16(n))), err
+	case 0xd2:
+		n, err = d.uint(4)
+		return mpInt, uint64(int64(int32(n))), err
+	case 0xd3:
+		n, err = d.uint(8)
+		return mpInt, n, err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		t, err := d.uint(1)
+		d.ext = int8(t)
+		return mpExt, 1 << (b - 0xd4), err
+	case 0xd9, 0xda, 0xdb:
+		n, err = d.uint(1 << (b - 0xd9))
+		return mpStr, n, err
+	case 0xdc, 0xdd:
+		n, err = d.uint(2 << (b - 0xdc))
+		return mpArray, n, err
+	case 0xde, 0xdf:
+		n, err = d.uint(2 << (b - 0xde))
+		return mpMap, n, err
+	}
+	return 0, 0, fmt.Errorf("msgpack: invalid format byte 0x%02x", b)
+}
+
+// collection checks that a header of n elements, each of at least one byte, fits the data.
+func (d *mpDecoder) collection(n uint64, perElem uint64, depth int) error {
+	if depth > mpMaxDepth {
+		return errors.New("msgpack: maximum nesting depth exceeded")
+	}
+	if n > uint64(len(d.data)-d.pos)/perElem {
+		return errShortData
+	}
+	return nil
+}
+
+func (d *mpDecoder) skip(depth int) error {
+	f, n, err := d.header()
+	if err != nil {
+		return err
+	}
+	switch f {
+	case mpStr, mpBin, mpExt:
+		_, err = d.read(n)
+	case mpArray, mpMap:
+		if f == mpMap {
+			n *= 2
+		}
+		if err = d.collection(n, 1, depth); err != nil {
+			return err
+		}
+		for i := uint64(0); i < n && err == nil; i++ {
+			err = d.skip(depth + 1)
+		}
+	}
+	return err
+}
+
+func (d *mpDecoder) decode(v reflect.Value, depth int) error {
+	start := d.pos
+	f, n, err := d.header()
+	if err != nil {
+		return err
+	}
+	if f == mpNil {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			v.SetZero()
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		d.pos = start
+		return d.decode(v.Elem(), depth)
+	}
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		d.pos = start
+		x, err := d.decodeAny(depth)
+		if err != nil {
+			return err
+		}
+		if x == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(x))
+		}
+		return nil
+	}
+	if v.Type() == timeType && f == mpExt {
+		t, err := d.decodeTime(n)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if f == mpStr && v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		bs, err := d.read(n)
+		if err != nil {
+			return err
+		}
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(bs)
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("msgpack: cannot decode %s into %s", f, v.Type())
+	}
+	switch f {
+	case mpBool:
+		if v.Kind() != reflect.Bool {
+			return mismatch()
+		}
+		v.SetBool(n == 1)
+	case mpInt, mpUint:
+		return d.setInt(v, f, n, mismatch)
+	case mpFloat:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(math.Float64frombits(n))
+		default:
+			return mismatch()
+		}
+	case mpStr, mpBin:
+		bs, err := d.read(n)
+		if err != nil {
+			return err
+		}
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(string(bs))
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetBytes(append([]byte(nil), bs...))
+		case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 && v.Len() == len(bs):
+			reflect.Copy(v, reflect.ValueOf(bs))
+		default:
+			return mismatch()
+		}
+	case mpArray:
+		if err := d.collection(n, 1, depth); err != nil {
+			return err
+		}
+		switch v.Kind() {
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), int(n), int(n)))
+		case reflect.Array:
+			if uint64(v.Len()) < n {
+				return fmt.Errorf("msgpack: array of %d elements does not fit %s", n, v.Type())
+			}
+			v.SetZero()
+		default:
+			return mismatch()
+		}
+		for i := 0; i < int(n); i++ {
+			if err := d.decode(v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case mpMap:
+		if err := d.collection(n, 2, depth); err != nil {
+			return err
+		}
+		switch v.Kind() {
+		case reflect.Map:
+			return d.decodeMap(v, int(n), depth)
+		case reflect.Struct:
+			return d.decodeStruct(v, int(n), depth)
+		default:
+			return mismatch()
+		}
+	case mpExt:
+		return mismatch()
+	}
+	return nil
+}
+
+func (d *mpDecoder) setInt(v reflect.Value, f mpFormat, n uint64, mismatch func() error) error {
+	negative := f == mpInt && int64(n) < 0
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if (!negative && n > math.MaxInt64) || v.OverflowInt(int64(n)) {
+			return fmt.Errorf("msgpack: %d overflows %s", n, v.Type())
+		}
+		v.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if negative || v.OverflowUint(n) {
+			return fmt.Errorf("msgpack: %d overflows %s", int64(n), v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if negative {
+			v.SetFloat(float64(int64(n)))
+		} else {
+			v.SetFloat(float64(n))
+		}
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+func (d *mpDecoder) decodeMap(v reflect.Value, n int, depth int) error {
+	t := v.Type()
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(t, n))
+	}
+	for i := 0; i < n; i++ {
+		k := reflect.New(t.Key()).Elem()
+		if err := d.decode(k, depth+1); err != nil {
+			return err
+		}
+		if !k.Comparable() {
+			return fmt.Errorf("msgpack: map key of type %s is not comparable", k.Type())
+		}
+		e := reflect.New(t.Elem()).Elem()
+		if err := d.decode(e, depth+1); err != nil {
+			return err
+		}
+		v.SetMapIndex(k, e)
+	}
+	return nil
+}
+
+// decodeStruct matches keys to fields by name, then case-insensitively like encoding/json.
+// Unknown keys are skipped.
+func (d *mpDecoder) decodeStruct(v reflect.Value, n int, depth int) error {
+	fields := mpFields(v.Type())
+	for i := 0; i < n; i++ {
+		var name string
+		if err := d.decode(reflect.ValueOf(&name).Elem(), depth+1); err != nil {
+			return err
+		}
+		var field *mpField
+		for j := range fields {
+			if fields[j].name == name {
+				field = &fields[j]
+				break
+			}
+			if field == nil && strings.EqualFold(fields[j].name, name) {
+				field = &fields[j]
+			}
+		}
+		if field == nil {
+			if err := d.skip(depth + 1); err != nil {
+				return err
+			}
+			continue
+		}
+		fv, _ := reflectx.FieldByIndex(v, field.index, true)
+		if err := d.decode(fv, depth+1); err != nil {
+			return fmt.Errorf("%w (field %s)", err, field.name)
+		}
+	}
+	return nil
+}
+
+func (d *mpDecoder) decodeAny(depth int) (any, error) {
+	f, n, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	switch f {
+	case mpNil:
+		return nil, nil
+	case mpBool:
+		return n == 1, nil
+	case mpInt:
+		return int64(n), nil
+	case mpUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case mpFloat:
+		return math.Float64frombits(n), nil
+	case mpStr:
+		bs, err := d.read(n)
+		return string(bs), err
+	case mpBin:
+		bs, err := d.read(n)
+		return append([]byte(nil), bs...), err
+	case mpArray:
+		if err := d.collection(n, 1, depth); err != nil {
+			return nil, err
+		}
+		rv := make([]any, n)
+		for i := range rv {
+			if rv[i], err = d.decodeAny(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return rv, nil
+	case mpMap:
+		if err := d.collection(n, 2, depth); err != nil {
+			return nil, err
+		}
+		return d.decodeAnyMap(int(n), depth)
+	case mpExt:
+		return d.decodeTime(n)
+	}
+	return nil, fmt.Errorf("msgpack: cannot decode %s", f)
+}
+
+// decodeAnyMap returns a map[string]any, or a map[any]any if a key is not a string.
+func (d *mpDecoder) decodeAnyMap(n int, depth int) (any, error) {
+	strMap := make(map[string]any, n)
+	var anyMap map[any]any
+	for i := 0; i < n; i++ {
+		k, err := d.decodeAny(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decodeAny(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := k.(string); ok && anyMap == nil {
+			strMap[s] = v
+			continue
+		}
+		if !reflect.ValueOf(k).Comparable() {
+			return nil, fmt.Errorf("msgpack: map key of type %T is not comparable", k)
+		}
+		if anyMap == nil {
+			anyMap = make(map[any]any, n)
+			for s, v := range strMap {
+				anyMap[s] = v
+			}
+		}
+		anyMap[k] = v
+	}
+	if anyMap != nil {
+		return anyMap, nil
+	}
+	return strMap, nil
+}
+
+func (d *mpDecoder) decodeTime(n uint64) (time.Time, error) {
+	if d.ext != mpTimestampExt {
+		return time.Time{}, fmt.Errorf("msgpack: unsupported extension type %d", d.ext)
+	}
+	bs, err := d.read(n)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var sec, nsec int64
+	switch n {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(bs))
+	case 8:
+		x := binary.BigEndian.Uint64(bs)
+		sec, nsec = int64(x&(1<<34-1)), int64(x>>34)
+	case 12:
+		sec, nsec = int64(binary.BigEndian.Uint64(bs[4:])), int64(binary.BigEndian.Uint32(bs))
+	default:
+		return time.Time{}, fmt.Errorf("msgpack: invalid timestamp of %d bytes", n)
+	}
+	// keep the zero time zero, so that IsZero still holds after a round trip
+	if sec == zeroUnix && nsec == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+var zeroUnix = time.Time{}.Unix()
@@ -0,0 +1,123 @@
+package codec
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsgPack_Encoding(t *testing.T) {
+	for _, tt := range []struct {
+		in   any
+		want []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{1, []byte{0x01}},
+		{-1, []byte{0xff}},
+		{200, []byte{0xcc, 0xc8}},
+		{-200, []byte{0xd1, 0xff, 0x38}},
+		{uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{[]byte{1, 2}, []byte{0xc4, 2, 1, 2}},
+		{[]int{1, 2}, []byte{0x92, 1, 2}},
+		{map[string]int{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 1, 0xa1, 'b', 2}},
+		{time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{time.Unix(1<<32-1, 0), []byte{0xd6, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{time.Unix(1<<32, 0), []byte{0xd7, 0xff, 0, 0, 0, 1, 0, 0, 0, 0}},
+	} {
+		got, err := MsgPack.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("Marshal(%v) error = %v", tt.in, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("Marshal(%v) = % x, want % x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMsgPack_TimeBoundaries(t *testing.T) {
+	for _, sec := range []int64{1<<32 - 1, 1 << 32, 1 << 33, 1<<34 - 1, 1 << 34, -1} {
+		for _, nsec := range []int64{0, 1} {
+			in := time.Unix(sec, nsec)
+			data, err := MsgPack.Marshal(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got time.Time
+			if err := MsgPack.Unmarshal(data, &got); err != nil || !got.Equal(in) {
+				t.Errorf("round trip of %v = %v, %v", in, got, err)
+			}
+		}
+	}
+}
+
+func TestMsgPack_Any(t *testing.T) {
+	in := map[string]any{
+		"n":    int64(-3),
+		"big":  uint64(math.MaxUint64),
+		"f":    0.25,
+		"s":    strings.Repeat("x", 300),
+		"list": []any{true, nil, "a"},
+		"m":    map[string]any{"k": []byte("v")},
+		"t":    time.Unix(1<<35, 5).UTC(),
+	}
+	bs, err := MsgPack.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := MsgPack.Unmarshal(bs, &out); err != nil {
+		t.Fatal(err)
+	}
+	m := out.(map[string]any)
+	m["t"] = m["t"].(time.Time).UTC()
+	if !reflect.DeepEqual(m, in) {
+		t.Errorf("round trip = %v, want %v", m, in)
+	}
+
+	bs, _ = MsgPack.Marshal(map[int]string{1: "a"})
+	if err := MsgPack.Unmarshal(bs, &out); err != nil || !reflect.DeepEqual(out, map[any]any{int64(1): "a"}) {
+		t.Errorf("non-string keys = %v, %v", out, err)
+	}
+}
+
+func TestMsgPack_Struct(t *testing.T) {
+	type Base struct {
+		ID int64 `msgpack:"id"`
+	}
+	type Order struct {
+		Base
+		*testItem `msgpack:"item"`
+		Side      string `json:"side"`
+		Qty       uint8
+		skipped   int
+	}
+	bs, err := MsgPack.Marshal(map[string]any{"id": 7, "side": "buy", "qty": 3, "extra": []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var o Order
+	if err := MsgPack.Unmarshal(bs, &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != 7 || o.Side != "buy" || o.Qty != 3 || o.testItem != nil {
+		t.Errorf("Unmarshal() = %+v", o)
+	}
+
+	bs, _ = MsgPack.Marshal(map[string]int{"Qty": 300})
+	if err := MsgPack.Unmarshal(bs, &o); err == nil {
+		t.Error("Unmarshal() of an overflowing value succeeded")
+	}
+	if err := MsgPack.Unmarshal([]byte{0x92, 0x01}, &[]int{}); err == nil {
+		t.Error("Unmarshal() of truncated data succeeded")
+	}
+	var v any
+	if err := MsgPack.Unmarshal([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}, &v); err == nil {
+		t.Error("Unmarshal() of an oversized array succeeded")
+	}
+}
@@ -0,0 +1,108 @@
+package collections
+
+import "iter"
+
+// BiMap is a one-to-one map: each key has one value and each value one key,
+// so it can be looked up in both directions.
+type BiMap[K, V comparable] struct {
+	fwd map[K]V
+	bwd map[V]K
+}
+
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{fwd: make(map[K]V), bwd: make(map[V]K)}
+}
+
+// Load returns the value of key.
+func (m *BiMap[K, V]) Load(key K) (value V, ok bool) {
+	value, ok = m.fwd[key]
+	return
+}
+
+// LoadKey returns the key of value.
+func (m *BiMap[K, V]) LoadKey(value V) (key K, ok bool) {
+	key, ok = m.bwd[value]
+	return
+}
+
+// Store binds key and value, dropping any previous pair holding either of them.
+func (m *BiMap[K, V]) Store(key K, value V) {
+	m.Delete(key)
+	m.DeleteValue(value)
+	m.fwd[key] = value
+	m.bwd[value] = key
+}
+
+// TryStore binds key and value unless either is already bound, and reports whether it did.
+// Storing a pair that is already present succeeds.
+func (m *BiMap[K, V]) TryStore(key K, value V) bool {
+	if v, ok := m.fwd[key]; ok {
+		return v == value
+	}
+	if _, ok := m.bwd[value]; ok {
+		return false
+	}
+	m.fwd[key] = value
+	m.bwd[value] = key
+	return true
+}
+
+// Delete removes key and its value.
+func (m *BiMap[K, V]) Delete(key K) {
+	if v, ok := m.fwd[key]; ok {
+		delete(m.fwd, key)
+		delete(m.bwd, v)
+	}
+}
+
+// DeleteValue removes value and its key.
+func (m *BiMap[K, V]) DeleteValue(value V) {
+	if k, ok := m.bwd[value]; ok {
+		delete(m.bwd, value)
+		delete(m.fwd, k)
+	}
+}
+
+// Inverse returns a view of m from values to keys. Both share the same storage.
+func (m *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{fwd: m.bwd, bwd: m.fwd}
+}
+
+func (m *BiMap[K, V]) Len() int {
+	return len(m.fwd)
+}
+
+func (m *BiMap[K, V]) Clear() {
+	clear(m.fwd)
+	clear(m.bwd)
+}
+
+func (m *BiMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.fwd {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func (m *BiMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range m.bwd {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (m *BiMap[K, V]) Items() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.fwd {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
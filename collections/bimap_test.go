@@ -0,0 +1,35 @@
+package collections
+
+import "testing"
+
+func TestBiMap(t *testing.T) {
+	m := NewBiMap[string, int]()
+	m.Store("a", 1)
+	m.Store("b", 2)
+	if k, _ := m.LoadKey(2); k != "b" {
+		t.Errorf("LoadKey(2) = %s, want b", k)
+	}
+	if m.TryStore("c", 1) {
+		t.Error("TryStore() of a bound value = true")
+	}
+	if !m.TryStore("a", 1) {
+		t.Error("TryStore() of an existing pair = false")
+	}
+	// rebinding drops the pairs holding a or 2
+	m.Store("a", 2)
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", m.Len())
+	}
+	if _, ok := m.Load("b"); ok {
+		t.Error("b still bound")
+	}
+	inv := m.Inverse()
+	inv.Store(3, "c")
+	if v, _ := m.Load("c"); v != 3 {
+		t.Errorf("Load(c) through the inverse = %d, want 3", v)
+	}
+	m.DeleteValue(2)
+	if _, ok := m.Load("a"); ok || m.Len() != 1 {
+		t.Error("DeleteValue() left the key")
+	}
+}
@@ -0,0 +1,146 @@
+package collections
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+)
+
+const bloomMagic = "BLM1"
+
+// BloomFilter is a probabilistic set of byte strings: Test never misses an added value,
+// but may report values that were not added with the configured false-positive rate.
+// Values cannot be removed, see CuckooFilter for that.
+type BloomFilter struct {
+	m     uint64 // number of bits
+	k     uint32 // number of hash functions
+	n     uint64 // number of values added
+	words []uint64
+}
+
+// NewBloomFilter creates a filter sized for n values with false-positive rate p.
+func NewBloomFilter(n uint64, p float64) *BloomFilter {
+	n = max(n, 1)
+	p = clampRate(p, 1e-12, 0.5)
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+	m = max(m, 64)
+	return &BloomFilter{m: m, k: max(k, 1), words: make([]uint64, (m+63)/64)}
+}
+
+func (f *BloomFilter) positions(data []byte, fn func(bit uint64) bool) bool {
+	h := hash64(data)
+	h1, h2 := h, mix64(h)|1
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !fn((h1 + i*h2) % f.m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BloomFilter) Add(data []byte) {
+	f.positions(data, func(bit uint64) bool {
+		f.words[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+	f.n++
+}
+
+func (f *BloomFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Test reports whether data may have been added.
+func (f *BloomFilter) Test(data []byte) bool {
+	return f.positions(data, func(bit uint64) bool {
+		return f.words[bit/64]&(1<<(bit%64)) != 0
+	})
+}
+
+func (f *BloomFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// TestAndAdd adds data and reports whether it may have been added before.
+func (f *BloomFilter) TestAndAdd(data []byte) bool {
+	ok := f.Test(data)
+	f.Add(data)
+	return ok
+}
+
+// Count returns the number of values added, duplicates included.
+func (f *BloomFilter) Count() uint64 {
+	return f.n
+}
+
+// Cap returns the number of bits and of hash functions.
+func (f *BloomFilter) Cap() (m uint64, k uint32) {
+	return f.m, f.k
+}
+
+// FalsePositiveRate estimates the current false-positive rate from the fill ratio.
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.words {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// Merge adds the values of other, which must have been created with the same parameters.
+func (f *BloomFilter) Merge(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return errors.New("collections: bloom filters are not compatible")
+	}
+	for i, w := range other.words {
+		f.words[i] |= w
+	}
+	f.n += other.n
+	return nil
+}
+
+func (f *BloomFilter) Clear() {
+	clear(f.words)
+	f.n = 0
+}
+
+// MarshalBinary encodes the filter as the magic "BLM1", m, k, n and the bit words, little-endian.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(bloomMagic)+20+8*len(f.words))
+	b = append(b, bloomMagic...)
+	b = binary.LittleEndian.AppendUint64(b, f.m)
+	b = binary.LittleEndian.AppendUint32(b, f.k)
+	b = binary.LittleEndian.AppendUint64(b, f.n)
+	for _, w := range f.words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b, nil
+}
+
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	const header = len(bloomMagic) + 20
+	if len(data) < header || string(data[:len(bloomMagic)]) != bloomMagic {
+		return ErrInvalidFilter
+	}
+	data = data[len(bloomMagic):]
+	m := binary.LittleEndian.Uint64(data)
+	k := binary.LittleEndian.Uint32(data[8:])
+	n := binary.LittleEndian.Uint64(data[12:])
+	data = data[20:]
+	// count the words from m without rounding m up, which could overflow
+	nwords := m / 64
+	if m%64 != 0 {
+		nwords++
+	}
+	if m == 0 || k == 0 || len(data)%8 != 0 || uint64(len(data))/8 != nwords {
+		return ErrInvalidFilter
+	}
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	f.m, f.k, f.n, f.words = m, k, n, words
+	return nil
+}
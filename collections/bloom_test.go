@@ -0,0 +1,64 @@
+package collections
+
+import (
+	"encoding/binary"
+	"strconv"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	f := NewBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("Test(%d) = false for an added value", i)
+		}
+	}
+	fp := 0
+	for i := 1000; i < 11000; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / 10000; rate > 0.02 {
+		t.Errorf("false-positive rate = %.4f, want about 0.01", rate)
+	}
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g BloomFilter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Count() != 1000 || !g.TestString("42") {
+		t.Errorf("unmarshaled filter lost values, Count() = %d", g.Count())
+	}
+	if err := g.UnmarshalBinary(data[:len(data)-1]); err != ErrInvalidFilter {
+		t.Errorf("UnmarshalBinary(truncated) = %v, want ErrInvalidFilter", err)
+	}
+
+	h := NewBloomFilter(1000, 0.01)
+	h.AddString("x")
+	if err := h.Merge(f); err != nil || !h.TestString("x") || !h.TestString("7") {
+		t.Errorf("Merge() = %v", err)
+	}
+	if err := h.Merge(NewBloomFilter(10, 0.1)); err == nil {
+		t.Error("Merge() of an incompatible filter succeeded")
+	}
+}
+
+func TestBloomFilter_UnmarshalHostile(t *testing.T) {
+	data, _ := NewBloomFilter(100, 0.01).MarshalBinary()
+	header := append([]byte(nil), data[:len(bloomMagic)+20]...)
+	for _, m := range []uint64{^uint64(0), ^uint64(0) - 63} {
+		binary.LittleEndian.PutUint64(header[len(bloomMagic):], m)
+		var f BloomFilter
+		if err := f.UnmarshalBinary(header); err != ErrInvalidFilter {
+			t.Errorf("UnmarshalBinary(m = %d, no words) = %v, want ErrInvalidFilter", m, err)
+		}
+	}
+}
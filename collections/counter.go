@@ -1,15 +1,17 @@
 package collections
 
 import (
+	"container/heap"
 	"iter"
 	"maps"
+	"slices"
 	"sort"
 )
 
 // Counter is a map of values to their counts.
 type Counter[T comparable] struct {
-	items map[T]int64
-	sorted []T
+	items    map[T]int64
+	sorted   []T
 	isSorted bool
 }
 
@@ -52,12 +54,17 @@ func (c *Counter[T]) Len() int {
 	return len(c.items)
 }
 
-
+// MostCommon yields the n most common values, most common first.
+// Unless the counter is already sorted, it runs in O(m log n) with a bounded heap
+// instead of sorting all the m keys.
 func (c *Counter[T]) MostCommon(n int) iter.Seq2[T, int64] {
-	if n > c.Len() {
-		n = c.Len()
+	n = max(min(n, c.Len()), 0)
+	var ks []T
+	if c.isSorted {
+		ks = c.sorted[:n]
+	} else {
+		ks = c.top(n, true)
 	}
-	ks := c.Sorted()[:n]
 	return func(yield func(T, int64) bool) {
 		for _, k := range ks {
 			if !yield(k, c.items[k]) {
@@ -67,14 +74,18 @@ func (c *Counter[T]) MostCommon(n int) iter.Seq2[T, int64] {
 	}
 }
 
+// LeastCommon yields the n least common values, least common first.
 func (c *Counter[T]) LeastCommon(n int) iter.Seq2[T, int64] {
-	if n > c.Len() {
-		n = c.Len()
+	n = max(min(n, c.Len()), 0)
+	var ks []T
+	if c.isSorted {
+		ks = slices.Clone(c.sorted[c.Len()-n:])
+		slices.Reverse(ks)
+	} else {
+		ks = c.top(n, false)
 	}
-	ks := c.Sorted()[c.Len()-n:]
 	return func(yield func(T, int64) bool) {
-		for i := 0; i < n; i++ {
-			k := ks[n-i-1]
+		for _, k := range ks {
 			if !yield(k, c.items[k]) {
 				return
 			}
@@ -82,6 +93,54 @@ func (c *Counter[T]) LeastCommon(n int) iter.Seq2[T, int64] {
 	}
 }
 
+// top selects the n keys with the highest (most) or lowest counts with a heap of size n,
+// and returns them from the most extreme to the least.
+func (c *Counter[T]) top(n int, most bool) []T {
+	if n <= 0 {
+		return nil
+	}
+	// the root of h is the weakest of the selected keys
+	h := &countHeap[T]{counts: c.items, most: most}
+	for k := range c.items {
+		if h.Len() < n {
+			heap.Push(h, k)
+		} else if h.better(k, h.keys[0]) {
+			h.keys[0] = k
+			heap.Fix(h, 0)
+		}
+	}
+	rv := make([]T, h.Len())
+	for i := len(rv) - 1; i >= 0; i-- {
+		rv[i] = heap.Pop(h).(T)
+	}
+	return rv
+}
+
+// countHeap is a heap of keys whose root is the key with the lowest count when most is true,
+// and the highest count otherwise.
+type countHeap[T comparable] struct {
+	keys   []T
+	counts map[T]int64
+	most   bool
+}
+
+func (h *countHeap[T]) better(a, b T) bool {
+	if h.most {
+		return h.counts[a] > h.counts[b]
+	}
+	return h.counts[a] < h.counts[b]
+}
+
+func (h *countHeap[T]) Len() int           { return len(h.keys) }
+func (h *countHeap[T]) Less(i, j int) bool { return h.better(h.keys[j], h.keys[i]) }
+func (h *countHeap[T]) Swap(i, j int)      { h.keys[i], h.keys[j] = h.keys[j], h.keys[i] }
+func (h *countHeap[T]) Push(x any)         { h.keys = append(h.keys, x.(T)) }
+func (h *countHeap[T]) Pop() any {
+	k := h.keys[len(h.keys)-1]
+	h.keys = h.keys[:len(h.keys)-1]
+	return k
+}
+
 func (c *Counter[T]) Delete(value T) {
 	delete(c.items, value)
 	c.isSorted = false
@@ -121,6 +180,65 @@ func (c *Counter[T]) Keys() iter.Seq[T] {
 	return maps.Keys(c.items)
 }
 
+// AddN adds n to the count of value. n may be negative.
+func (c *Counter[T]) AddN(value T, n int64) {
+	c.items[value] += n
+	c.isSorted = false
+}
 
+// Total returns the sum of all the counts.
+func (c *Counter[T]) Total() int64 {
+	var total int64
+	for _, v := range c.items {
+		total += v
+	}
+	return total
+}
 
+// Positive returns a copy keeping only the positive counts, like +counter in Python.
+func (c *Counter[T]) Positive() *Counter[T] {
+	rv := NewCounter[T]()
+	for k, v := range c.items {
+		if v > 0 {
+			rv.items[k] = v
+		}
+	}
+	return rv
+}
+
+// Intersect returns the minimum of the counts of both counters, keeping positive counts only,
+// like counter & other in Python.
+func (c *Counter[T]) Intersect(other *Counter[T]) *Counter[T] {
+	rv := NewCounter[T]()
+	for k, v := range c.items {
+		if n := min(v, other.items[k]); n > 0 {
+			rv.items[k] = n
+		}
+	}
+	return rv
+}
 
+// Union returns the maximum of the counts of both counters, keeping positive counts only,
+// like counter | other in Python.
+func (c *Counter[T]) Union(other *Counter[T]) *Counter[T] {
+	rv := c.Positive()
+	for k, v := range other.items {
+		if v > rv.items[k] {
+			rv.items[k] = v
+		}
+	}
+	return rv
+}
+
+// Elements yields each value as many times as its count. Values with a count below one are skipped.
+func (c *Counter[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for k, v := range c.items {
+			for i := int64(0); i < v; i++ {
+				if !yield(k) {
+					return
+				}
+			}
+		}
+	}
+}
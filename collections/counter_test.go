@@ -211,3 +211,64 @@ func TestCounter_Keys(t *testing.T) {
 		t.Error("Keys() missing some keys")
 	}
 }
+
+func TestCounter_TopHeap(t *testing.T) {
+	c := NewCounter[int]()
+	for i := 1; i <= 100; i++ {
+		for j := 0; j < i; j++ {
+			c.Add(i)
+		}
+	}
+	var most, least []int
+	for k := range c.MostCommon(3) {
+		most = append(most, k)
+	}
+	for k := range c.LeastCommon(3) {
+		least = append(least, k)
+	}
+	if len(most) != 3 || most[0] != 100 || most[1] != 99 || most[2] != 98 {
+		t.Errorf("MostCommon(3) = %v, want [100 99 98]", most)
+	}
+	if len(least) != 3 || least[0] != 1 || least[1] != 2 || least[2] != 3 {
+		t.Errorf("LeastCommon(3) = %v, want [1 2 3]", least)
+	}
+	if c.isSorted {
+		t.Error("MostCommon() should not sort all the keys")
+	}
+	for range c.MostCommon(0) {
+		t.Error("MostCommon(0) yielded a value")
+	}
+}
+
+func TestCounter_Arithmetic(t *testing.T) {
+	a := NewCounter[string]()
+	a.AddAll("x", "x", "x", "y")
+	a.AddN("z", -2)
+	b := NewCounter[string]()
+	b.AddAll("x", "y", "y", "w")
+
+	if got := a.Total(); got != 2 {
+		t.Errorf("Total() = %d, want 2", got)
+	}
+	if p := a.Positive(); p.Len() != 2 || p.Get("z") != 0 {
+		t.Errorf("Positive() = %v", p.Items())
+	}
+	i := a.Intersect(b)
+	if i.Len() != 2 || i.Get("x") != 1 || i.Get("y") != 1 {
+		t.Errorf("Intersect() = %v", i.Items())
+	}
+	u := a.Union(b)
+	if u.Len() != 3 || u.Get("x") != 3 || u.Get("y") != 2 || u.Get("w") != 1 {
+		t.Errorf("Union() = %v", u.Items())
+	}
+	n := 0
+	for v := range a.Elements() {
+		if v == "z" {
+			t.Errorf("Elements() yielded a negative count")
+		}
+		n++
+	}
+	if n != 4 {
+		t.Errorf("Elements() yielded %d values, want 4", n)
+	}
+}
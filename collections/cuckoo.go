@@ -0,0 +1,221 @@
+package collections
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+)
+
+const (
+	cuckooMagic    = "CKO1"
+	cuckooSlots    = 4   // fingerprints per bucket
+	cuckooMaxKicks = 500 // relocations tried before an insert fails
+	// CuckooMinRate is the lowest false-positive rate of a CuckooFilter, that of its
+	// widest, 16-bit fingerprints: about 1.2e-4.
+	CuckooMinRate = 2 * cuckooSlots / float64(1<<16)
+)
+
+// CuckooFilter is a probabilistic set of byte strings that, unlike BloomFilter, supports Delete.
+// Test never misses an added value, but may report values that were not added with
+// the configured false-positive rate. Deleting a value that was not added may remove
+// another value that shares its fingerprint.
+type CuckooFilter struct {
+	buckets uint64 // number of buckets, a power of two
+	fpBits  uint8  // fingerprint size, 8 or 16
+	n       uint64
+	table   []uint16 // buckets * cuckooSlots fingerprints, 0 is an empty slot
+	victim  uint16   // fingerprint evicted by a failed insert, kept so that Test never misses
+	vidx    uint64
+}
+
+// NewCuckooFilter creates a filter sized for n values with false-positive rate p.
+// Fingerprints take 8 bits when p allows it, 16 bits otherwise. A p below CuckooMinRate
+// is raised to it; use a BloomFilter for lower rates.
+func NewCuckooFilter(n uint64, p float64) *CuckooFilter {
+	p = clampRate(p, CuckooMinRate, 1)
+	fpBits := uint8(16)
+	if 2*cuckooSlots/p <= 1<<8 {
+		fpBits = 8
+	}
+	// keep the load factor below 95%
+	buckets := uint64(math.Ceil(float64(max(n, 1)) / cuckooSlots / 0.95))
+	buckets = 1 << bits.Len64(buckets-1)
+	return &CuckooFilter{buckets: buckets, fpBits: fpBits, table: make([]uint16, buckets*cuckooSlots)}
+}
+
+func (f *CuckooFilter) locate(data []byte) (fp uint16, i1, i2 uint64) {
+	h := hash64(data)
+	fp = uint16(h>>48) & (1<<f.fpBits - 1)
+	if fp == 0 {
+		fp = 1
+	}
+	i1 = h & (f.buckets - 1)
+	return fp, i1, f.alt(i1, fp)
+}
+
+// alt returns the other bucket of fp, so that alt(alt(i, fp), fp) == i.
+func (f *CuckooFilter) alt(i uint64, fp uint16) uint64 {
+	return (i ^ mix64(uint64(fp))) & (f.buckets - 1)
+}
+
+func (f *CuckooFilter) bucket(i uint64) []uint16 {
+	return f.table[i*cuckooSlots : (i+1)*cuckooSlots]
+}
+
+func (f *CuckooFilter) insert(i uint64, fp uint16) bool {
+	b := f.bucket(i)
+	for j, v := range b {
+		if v == 0 {
+			b[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds data and reports whether it did. It fails once the filter is full.
+func (f *CuckooFilter) Add(data []byte) bool {
+	if f.victim != 0 {
+		return false
+	}
+	fp, i1, i2 := f.locate(data)
+	if f.insert(i1, fp) || f.insert(i2, fp) {
+		f.n++
+		return true
+	}
+	i := i1
+	if rand.IntN(2) == 1 {
+		i = i2
+	}
+	for range cuckooMaxKicks {
+		j := rand.IntN(cuckooSlots)
+		b := f.bucket(i)
+		fp, b[j] = b[j], fp
+		i = f.alt(i, fp)
+		if f.insert(i, fp) {
+			f.n++
+			return true
+		}
+	}
+	// data is stored, but the last evicted fingerprint has no room left
+	f.victim, f.vidx = fp, i
+	f.n++
+	return true
+}
+
+func (f *CuckooFilter) AddString(s string) bool {
+	return f.Add([]byte(s))
+}
+
+// Test reports whether data may have been added.
+func (f *CuckooFilter) Test(data []byte) bool {
+	fp, i1, i2 := f.locate(data)
+	if f.victim == fp && (f.vidx == i1 || f.vidx == i2) {
+		return true
+	}
+	for _, i := range [2]uint64{i1, i2} {
+		for _, v := range f.bucket(i) {
+			if v == fp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// Delete removes one occurrence of data and reports whether it was found.
+func (f *CuckooFilter) Delete(data []byte) bool {
+	fp, i1, i2 := f.locate(data)
+	if f.victim == fp && (f.vidx == i1 || f.vidx == i2) {
+		f.victim = 0
+		f.n--
+		return true
+	}
+	for _, i := range [2]uint64{i1, i2} {
+		b := f.bucket(i)
+		for j, v := range b {
+			if v == fp {
+				b[j] = 0
+				f.n--
+				f.reinsertVictim()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter) DeleteString(s string) bool {
+	return f.Delete([]byte(s))
+}
+
+// reinsertVictim moves the victim back into the table once a slot may have been freed.
+func (f *CuckooFilter) reinsertVictim() {
+	if f.victim == 0 {
+		return
+	}
+	if f.insert(f.vidx, f.victim) || f.insert(f.alt(f.vidx, f.victim), f.victim) {
+		f.victim = 0
+	}
+}
+
+// Count returns the number of values stored.
+func (f *CuckooFilter) Count() uint64 {
+	return f.n
+}
+
+// LoadFactor returns the ratio of used slots.
+func (f *CuckooFilter) LoadFactor() float64 {
+	return float64(f.n) / float64(len(f.table))
+}
+
+func (f *CuckooFilter) Clear() {
+	clear(f.table)
+	f.n, f.victim, f.vidx = 0, 0, 0
+}
+
+// MarshalBinary encodes the filter as the magic "CKO1", the bucket count, the fingerprint size,
+// the count, the victim and the fingerprints, little-endian.
+func (f *CuckooFilter) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(cuckooMagic)+27+2*len(f.table))
+	b = append(b, cuckooMagic...)
+	b = binary.LittleEndian.AppendUint64(b, f.buckets)
+	b = append(b, f.fpBits)
+	b = binary.LittleEndian.AppendUint64(b, f.n)
+	b = binary.LittleEndian.AppendUint16(b, f.victim)
+	b = binary.LittleEndian.AppendUint64(b, f.vidx)
+	for _, fp := range f.table {
+		b = binary.LittleEndian.AppendUint16(b, fp)
+	}
+	return b, nil
+}
+
+func (f *CuckooFilter) UnmarshalBinary(data []byte) error {
+	const header = len(cuckooMagic) + 27
+	if len(data) < header || string(data[:len(cuckooMagic)]) != cuckooMagic {
+		return ErrInvalidFilter
+	}
+	data = data[len(cuckooMagic):]
+	buckets := binary.LittleEndian.Uint64(data)
+	fpBits := data[8]
+	n := binary.LittleEndian.Uint64(data[9:])
+	victim := binary.LittleEndian.Uint16(data[17:])
+	vidx := binary.LittleEndian.Uint64(data[19:])
+	data = data[27:]
+	// compare the bucket count before multiplying it, which could overflow
+	if buckets == 0 || buckets&(buckets-1) != 0 || (fpBits != 8 && fpBits != 16) || vidx >= buckets ||
+		buckets > uint64(len(data))/(cuckooSlots*2) || uint64(len(data)) != buckets*cuckooSlots*2 {
+		return ErrInvalidFilter
+	}
+	table := make([]uint16, buckets*cuckooSlots)
+	for i := range table {
+		table[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	f.buckets, f.fpBits, f.n, f.victim, f.vidx, f.table = buckets, fpBits, n, victim, vidx, table
+	return nil
+}
@@ -0,0 +1,112 @@
+package collections
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestCuckooFilter(t *testing.T) {
+	f := NewCuckooFilter(1000, 0.001)
+	for i := 0; i < 1000; i++ {
+		if !f.AddString(strconv.Itoa(i)) {
+			t.Fatalf("Add(%d) failed below capacity", i)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("Test(%d) = false for an added value", i)
+		}
+	}
+	fp := 0
+	for i := 1000; i < 11000; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / 10000; rate > 0.002 {
+		t.Errorf("false-positive rate = %.4f, want about 0.001", rate)
+	}
+
+	for i := 0; i < 500; i++ {
+		if !f.DeleteString(strconv.Itoa(i)) {
+			t.Fatalf("Delete(%d) = false", i)
+		}
+	}
+	if f.Count() != 500 {
+		t.Errorf("Count() = %d, want 500", f.Count())
+	}
+	for i := 500; i < 1000; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("Test(%d) = false after deleting other values", i)
+		}
+	}
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g CuckooFilter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Count() != 500 || !g.TestString("999") {
+		t.Errorf("unmarshaled filter lost values, Count() = %d", g.Count())
+	}
+	if err := g.UnmarshalBinary([]byte("BLM1")); err != ErrInvalidFilter {
+		t.Errorf("UnmarshalBinary(garbage) = %v, want ErrInvalidFilter", err)
+	}
+}
+
+func TestCuckooFilter_UnmarshalHostile(t *testing.T) {
+	data, _ := NewCuckooFilter(100, 0.01).MarshalBinary()
+	header := append([]byte(nil), data[:len(cuckooMagic)+27]...)
+	for _, buckets := range []uint64{1 << 62, 1 << 63} {
+		binary.LittleEndian.PutUint64(header[len(cuckooMagic):], buckets)
+		var f CuckooFilter
+		if err := f.UnmarshalBinary(header); err != ErrInvalidFilter {
+			t.Errorf("UnmarshalBinary(buckets = %d, no table) = %v, want ErrInvalidFilter", buckets, err)
+		}
+	}
+}
+
+func TestCuckooFilter_MinRate(t *testing.T) {
+	for _, p := range []float64{1e-9, 0, -1, math.NaN(), CuckooMinRate} {
+		f := NewCuckooFilter(1000, p)
+		if f.fpBits != 16 && !math.IsNaN(p) {
+			t.Errorf("NewCuckooFilter(%v) fingerprint bits = %d, want 16", p, f.fpBits)
+		}
+		for i := 0; i < 1000; i++ {
+			f.AddString(strconv.Itoa(i))
+		}
+		fp := 0
+		for i := 1000; i < 101000; i++ {
+			if f.TestString(strconv.Itoa(i)) {
+				fp++
+			}
+		}
+		if rate := float64(fp) / 100000; !math.IsNaN(p) && rate > 2*CuckooMinRate {
+			t.Errorf("NewCuckooFilter(%v) false-positive rate = %v, want at most about %v", p, rate, CuckooMinRate)
+		}
+	}
+}
+
+func TestCuckooFilter_Full(t *testing.T) {
+	f := NewCuckooFilter(8, 0.1)
+	added := 0
+	for i := 0; i < 1000; i++ {
+		if !f.AddString(strconv.Itoa(i)) {
+			break
+		}
+		added++
+	}
+	if added == 1000 {
+		t.Fatal("Add() never reported a full filter")
+	}
+	for i := 0; i < added; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("Test(%d) = false for an added value", i)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package collections
+
+import "iter"
+
+// Deque is a double-ended queue backed by a growable ring buffer.
+// The zero value is an empty deque ready to use.
+type Deque[T any] struct {
+	buf  []T
+	head int // index of the front value
+	n    int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{buf: make([]T, max(capacity, 0))}
+}
+
+func (d *Deque[T]) grow() {
+	if d.n < len(d.buf) {
+		return
+	}
+	buf := make([]T, max(2*len(d.buf), 8))
+	for i := 0; i < d.n; i++ {
+		buf[i] = d.buf[(d.head+i)%len(d.buf)]
+	}
+	d.buf, d.head = buf, 0
+}
+
+func (d *Deque[T]) PushBack(value T) {
+	d.grow()
+	d.buf[(d.head+d.n)%len(d.buf)] = value
+	d.n++
+}
+
+func (d *Deque[T]) PushFront(value T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = value
+	d.n++
+}
+
+func (d *Deque[T]) PopFront() (value T, ok bool) {
+	if d.n == 0 {
+		return value, false
+	}
+	var zero T
+	value, d.buf[d.head] = d.buf[d.head], zero
+	d.head = (d.head + 1) % len(d.buf)
+	d.n--
+	return value, true
+}
+
+func (d *Deque[T]) PopBack() (value T, ok bool) {
+	if d.n == 0 {
+		return value, false
+	}
+	var zero T
+	i := (d.head + d.n - 1) % len(d.buf)
+	value, d.buf[i] = d.buf[i], zero
+	d.n--
+	return value, true
+}
+
+func (d *Deque[T]) Front() (value T, ok bool) {
+	if d.n == 0 {
+		return value, false
+	}
+	return d.buf[d.head], true
+}
+
+func (d *Deque[T]) Back() (value T, ok bool) {
+	if d.n == 0 {
+		return value, false
+	}
+	return d.buf[(d.head+d.n-1)%len(d.buf)], true
+}
+
+// At returns the i-th value from the front. It panics if i is out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.n {
+		panic("collections: Deque index out of range")
+	}
+	return d.buf[(d.head+i)%len(d.buf)]
+}
+
+func (d *Deque[T]) Len() int {
+	return d.n
+}
+
+func (d *Deque[T]) IsEmpty() bool {
+	return d.n == 0
+}
+
+func (d *Deque[T]) Clear() {
+	clear(d.buf)
+	d.head, d.n = 0, 0
+}
+
+// ToSeq yields the values from front to back.
+func (d *Deque[T]) ToSeq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.n; i++ {
+			if !yield(d.buf[(d.head+i)%len(d.buf)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward yields the values from back to front.
+func (d *Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := d.n - 1; i >= 0; i-- {
+			if !yield(d.buf[(d.head+i)%len(d.buf)]) {
+				return
+			}
+		}
+	}
+}
+
+func (d *Deque[T]) ToSlice() []T {
+	rv := make([]T, 0, d.n)
+	for v := range d.ToSeq() {
+		rv = append(rv, v)
+	}
+	return rv
+}
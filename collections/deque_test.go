@@ -0,0 +1,40 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	for i := 0; i < 10; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	if d.Len() != 20 {
+		t.Errorf("Len() = %d, want 20", d.Len())
+	}
+	if v, _ := d.Front(); v != -10 {
+		t.Errorf("Front() = %d, want -10", v)
+	}
+	if v, _ := d.Back(); v != 9 {
+		t.Errorf("Back() = %d, want 9", v)
+	}
+	if v := d.At(10); v != 0 {
+		t.Errorf("At(10) = %d, want 0", v)
+	}
+	for i := 0; i < 9; i++ {
+		d.PopFront()
+		d.PopBack()
+	}
+	if got, want := d.ToSlice(), []int{-1, 0}; !slices.Equal(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(d.Backward()), []int{0, -1}; !slices.Equal(got, want) {
+		t.Errorf("Backward() = %v, want %v", got, want)
+	}
+	d.Clear()
+	if _, ok := d.PopBack(); ok {
+		t.Error("PopBack() on empty deque returned ok")
+	}
+}
@@ -0,0 +1,176 @@
+package collections
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// sortItems sorts items in natural order when T is a string, number or bool,
+// and by their JSON encoding otherwise, so that encoded output is deterministic.
+func sortItems[T comparable](items []T) error {
+	if len(items) < 2 {
+		return nil
+	}
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String:
+		slices.SortFunc(items, func(a, b T) int {
+			return strings.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		slices.SortFunc(items, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		slices.SortFunc(items, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		})
+	case reflect.Float32, reflect.Float64:
+		slices.SortFunc(items, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		})
+	case reflect.Bool:
+		slices.SortFunc(items, func(a, b T) int {
+			if reflect.ValueOf(a).Bool() == reflect.ValueOf(b).Bool() {
+				return 0
+			}
+			if reflect.ValueOf(a).Bool() {
+				return 1
+			}
+			return -1
+		})
+	default:
+		keys := make(map[T][]byte, len(items))
+		for _, item := range items {
+			bs, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			keys[item] = bs
+		}
+		slices.SortFunc(items, func(a, b T) int {
+			return bytes.Compare(keys[a], keys[b])
+		})
+	}
+	return nil
+}
+
+// MarshalJSON encodes the set as a sorted array.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	items := make([]T, 0, len(s.items))
+	for item := range s.items {
+		items = append(items, item)
+	}
+	if err := sortItems(items); err != nil {
+		return nil, err
+	}
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON replaces the content of the set with a decoded array.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	s.items = make(map[T]struct{}, len(items))
+	s.Add(items...)
+	return nil
+}
+
+// MarshalText encodes the set as a sorted JSON array.
+func (s Set[T]) MarshalText() ([]byte, error) {
+	return s.MarshalJSON()
+}
+
+// UnmarshalText decodes either a JSON array or a comma separated list such as "a, b, c".
+// Each element of a list is decoded as JSON if possible, and taken as a bare string otherwise.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	text = bytes.TrimSpace(text)
+	if len(text) > 0 && text[0] == '[' {
+		return s.UnmarshalJSON(text)
+	}
+	s.items = make(map[T]struct{})
+	if len(text) == 0 {
+		return nil
+	}
+	for _, tok := range strings.Split(string(text), ",") {
+		tok = strings.TrimSpace(tok)
+		var item T
+		if err := json.Unmarshal([]byte(tok), &item); err != nil {
+			quoted, _ := json.Marshal(tok)
+			if err := json.Unmarshal(quoted, &item); err != nil {
+				return fmt.Errorf("invalid set element %q: %w", tok, err)
+			}
+		}
+		s.Add(item)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the counter as an object when T is a string type,
+// and as an array of [key, count] pairs sorted by key otherwise.
+func (c Counter[T]) MarshalJSON() ([]byte, error) {
+	if reflect.TypeFor[T]().Kind() == reflect.String {
+		return json.Marshal(c.items)
+	}
+	keys := make([]T, 0, len(c.items))
+	for k := range c.items {
+		keys = append(keys, k)
+	}
+	if err := sortItems(keys); err != nil {
+		return nil, err
+	}
+	pairs := make([][2]any, len(keys))
+	for i, k := range keys {
+		pairs[i] = [2]any{k, c.items[k]}
+	}
+	return json.Marshal(pairs)
+}
+
+// UnmarshalJSON replaces the content of the counter with a decoded object or array of pairs.
+func (c *Counter[T]) UnmarshalJSON(data []byte) error {
+	items := make(map[T]int64)
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var pairs [][2]json.RawMessage
+		if err := json.Unmarshal(trimmed, &pairs); err != nil {
+			return err
+		}
+		for _, p := range pairs {
+			var k T
+			var n int64
+			if err := json.Unmarshal(p[0], &k); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(p[1], &n); err != nil {
+				return err
+			}
+			items[k] += n
+		}
+	} else if err := json.Unmarshal(trimmed, &items); err != nil {
+		return err
+	}
+	c.items = items
+	c.sorted = nil
+	c.isSorted = false
+	return nil
+}
+
+// MarshalText encodes the counter like MarshalJSON.
+func (c Counter[T]) MarshalText() ([]byte, error) {
+	return c.MarshalJSON()
+}
+
+// UnmarshalText decodes the JSON form produced by MarshalText.
+func (c *Counter[T]) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		c.Clear()
+		return nil
+	}
+	return c.UnmarshalJSON(text)
+}
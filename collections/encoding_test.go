@@ -0,0 +1,97 @@
+package collections
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chenyan/wheels/codec"
+)
+
+func TestSet_JSON(t *testing.T) {
+	s := NewSetFromSlice([]string{"c", "a", "b"})
+	if got := codec.JSONString(s); got != `["a","b","c"]` {
+		t.Errorf("JSONString(set) = %s", got)
+	}
+	ints := NewSetFromSlice([]int{10, -1, 2})
+	if got := codec.JSONString(ints); got != `[-1,2,10]` {
+		t.Errorf("JSONString(int set) = %s", got)
+	}
+	type point struct{ X, Y int }
+	points := NewSetFromSlice([]point{{2, 1}, {1, 2}})
+	if got := codec.JSONString(points); got != `[{"X":1,"Y":2},{"X":2,"Y":1}]` {
+		t.Errorf("JSONString(struct set) = %s", got)
+	}
+
+	var conf struct {
+		Tags Set[string] `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(`{"tags":["x","y","x"]}`), &conf); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if conf.Tags.Len() != 2 || !conf.Tags.Contains("x") || !conf.Tags.Contains("y") {
+		t.Errorf("Unmarshal() tags = %v", conf.Tags.ToSlice())
+	}
+	if got := codec.JSONString(conf); got != `{"tags":["x","y"]}` {
+		t.Errorf("JSONString(conf) = %s", got)
+	}
+}
+
+func TestSet_Text(t *testing.T) {
+	s := NewSet[int]()
+	if err := s.UnmarshalText([]byte("3, 1,2")); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+	if text, _ := s.MarshalText(); string(text) != "[1,2,3]" {
+		t.Errorf("MarshalText() = %s", text)
+	}
+	if err := s.UnmarshalText([]byte("1, x")); err == nil {
+		t.Error("UnmarshalText() with invalid int expected error")
+	}
+
+	strs := NewSet[string]()
+	if err := strs.UnmarshalText([]byte(`a, "b"`)); err != nil || !strs.Contains("a") || !strs.Contains("b") {
+		t.Errorf("UnmarshalText() = %v, %v", strs.ToSlice(), err)
+	}
+	if err := strs.UnmarshalText([]byte(`["a","b"]`)); err != nil || strs.Len() != 2 {
+		t.Errorf("UnmarshalText(json) = %v, %v", strs.ToSlice(), err)
+	}
+}
+
+func TestCounter_JSON(t *testing.T) {
+	c := NewCounter[string]()
+	c.AddAll("b", "a", "b")
+	if got := codec.JSONString(c); got != `{"a":1,"b":2}` {
+		t.Errorf("JSONString(counter) = %s", got)
+	}
+
+	ints := NewCounter[int]()
+	ints.AddAll(3, 1, 3)
+	if got := codec.JSONString(ints); got != `[[1,1],[3,2]]` {
+		t.Errorf("JSONString(int counter) = %s", got)
+	}
+
+	var decoded Counter[int]
+	if err := json.Unmarshal([]byte(`[[1,1],[3,2]]`), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.Get(3) != 2 || decoded.Len() != 2 {
+		t.Errorf("Unmarshal() counter = %v", decoded.Items())
+	}
+	if k, _ := first(decoded.MostCommon(1)); k != 3 {
+		t.Errorf("MostCommon(1) after Unmarshal() = %v, want 3", k)
+	}
+
+	var words Counter[string]
+	if err := words.UnmarshalText([]byte(`{"go":5}`)); err != nil || words.Get("go") != 5 {
+		t.Errorf("UnmarshalText() = %v, %v", words.Items(), err)
+	}
+}
+
+func first[K, V any](seq func(func(K, V) bool)) (K, V) {
+	var k K
+	var v V
+	for k, v = range seq {
+		break
+	}
+	return k, v
+}
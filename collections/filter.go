@@ -0,0 +1,26 @@
+package collections
+
+import (
+	"errors"
+	"hash/fnv"
+)
+
+// ErrInvalidFilter is returned when unmarshaling data that is not a valid filter encoding.
+var ErrInvalidFilter = errors.New("collections: invalid filter data")
+
+// hash64 is a hash stable across processes, so that persisted filters stay valid.
+func hash64(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return mix64(h.Sum64())
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,137 @@
+package collections
+
+import (
+	"container/list"
+	"iter"
+)
+
+type linkedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LinkedHashMap is a map that remembers insertion order.
+// Storing a key that is already present keeps its position.
+type LinkedHashMap[K comparable, V any] struct {
+	items map[K]*list.Element
+	order *list.List
+}
+
+func NewLinkedHashMap[K comparable, V any]() *LinkedHashMap[K, V] {
+	return &LinkedHashMap[K, V]{items: make(map[K]*list.Element), order: list.New()}
+}
+
+func (m *LinkedHashMap[K, V]) Load(key K) (value V, ok bool) {
+	el, ok := m.items[key]
+	if !ok {
+		return value, false
+	}
+	return el.Value.(*linkedEntry[K, V]).value, true
+}
+
+// Store sets the value of key, appending key at the back if it is new.
+func (m *LinkedHashMap[K, V]) Store(key K, value V) {
+	if el, ok := m.items[key]; ok {
+		el.Value.(*linkedEntry[K, V]).value = value
+		return
+	}
+	m.items[key] = m.order.PushBack(&linkedEntry[K, V]{key: key, value: value})
+}
+
+func (m *LinkedHashMap[K, V]) Delete(key K) {
+	m.LoadAndDelete(key)
+}
+
+func (m *LinkedHashMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	el, ok := m.items[key]
+	if !ok {
+		return value, false
+	}
+	delete(m.items, key)
+	return m.order.Remove(el).(*linkedEntry[K, V]).value, true
+}
+
+// MoveToFront moves key to the front and reports whether key is present.
+func (m *LinkedHashMap[K, V]) MoveToFront(key K) bool {
+	el, ok := m.items[key]
+	if ok {
+		m.order.MoveToFront(el)
+	}
+	return ok
+}
+
+// MoveToBack moves key to the back and reports whether key is present.
+func (m *LinkedHashMap[K, V]) MoveToBack(key K) bool {
+	el, ok := m.items[key]
+	if ok {
+		m.order.MoveToBack(el)
+	}
+	return ok
+}
+
+// Front returns the first entry.
+func (m *LinkedHashMap[K, V]) Front() (key K, value V, ok bool) {
+	return m.entry(m.order.Front())
+}
+
+// Back returns the last entry.
+func (m *LinkedHashMap[K, V]) Back() (key K, value V, ok bool) {
+	return m.entry(m.order.Back())
+}
+
+func (m *LinkedHashMap[K, V]) entry(el *list.Element) (key K, value V, ok bool) {
+	if el == nil {
+		return key, value, false
+	}
+	e := el.Value.(*linkedEntry[K, V])
+	return e.key, e.value, true
+}
+
+func (m *LinkedHashMap[K, V]) Contains(key K) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+func (m *LinkedHashMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+func (m *LinkedHashMap[K, V]) Clear() {
+	m.items = make(map[K]*list.Element)
+	m.order.Init()
+}
+
+func (m *LinkedHashMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.Items() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func (m *LinkedHashMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.Items() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Items yields the entries from front to back. Deleting the current key during
+// the iteration is allowed.
+func (m *LinkedHashMap[K, V]) Items() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for el := m.order.Front(); el != nil; {
+			next := el.Next()
+			e := el.Value.(*linkedEntry[K, V])
+			if !yield(e.key, e.value) {
+				return
+			}
+			el = next
+		}
+	}
+}
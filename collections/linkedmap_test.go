@@ -0,0 +1,34 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinkedHashMap(t *testing.T) {
+	m := NewLinkedHashMap[string, int]()
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("c", 3)
+	m.Store("a", 10)
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("Keys() = %v", got)
+	}
+	m.MoveToBack("a")
+	m.MoveToFront("c")
+	if got := slices.Collect(m.Values()); !slices.Equal(got, []int{3, 2, 10}) {
+		t.Errorf("Values() = %v", got)
+	}
+	if k, v, _ := m.Back(); k != "a" || v != 10 {
+		t.Errorf("Back() = %s:%d, want a:10", k, v)
+	}
+	for k := range m.Keys() {
+		m.Delete(k)
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len() after deleting during iteration = %d", m.Len())
+	}
+	if _, _, ok := m.Front(); ok {
+		t.Error("Front() on empty map returned ok")
+	}
+}
@@ -0,0 +1,123 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+)
+
+// MultiMap maps each key to a list of values, in the order they were added.
+type MultiMap[K comparable, V any] struct {
+	items map[K][]V
+	n     int
+}
+
+func NewMultiMap[K comparable, V any]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{items: make(map[K][]V)}
+}
+
+// Add appends values to the list of key.
+func (m *MultiMap[K, V]) Add(key K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+	m.items[key] = append(m.items[key], values...)
+	m.n += len(values)
+}
+
+// Get returns the values of key. The returned slice must not be modified.
+func (m *MultiMap[K, V]) Get(key K) []V {
+	return m.items[key]
+}
+
+// Set replaces the values of key. An empty values deletes key.
+func (m *MultiMap[K, V]) Set(key K, values ...V) {
+	m.Delete(key)
+	m.Add(key, slices.Clone(values)...)
+}
+
+func (m *MultiMap[K, V]) Contains(key K) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+// Delete removes key and all its values.
+func (m *MultiMap[K, V]) Delete(key K) {
+	m.n -= len(m.items[key])
+	delete(m.items, key)
+}
+
+// DeleteFunc removes the values of key for which del returns true, and returns how many were removed.
+func (m *MultiMap[K, V]) DeleteFunc(key K, del func(V) bool) int {
+	vs, ok := m.items[key]
+	if !ok {
+		return 0
+	}
+	kept := slices.DeleteFunc(vs, del)
+	removed := len(vs) - len(kept)
+	m.n -= removed
+	if len(kept) == 0 {
+		delete(m.items, key)
+	} else {
+		m.items[key] = kept
+	}
+	return removed
+}
+
+// Len returns the number of values across all keys.
+func (m *MultiMap[K, V]) Len() int {
+	return m.n
+}
+
+// KeyLen returns the number of keys.
+func (m *MultiMap[K, V]) KeyLen() int {
+	return len(m.items)
+}
+
+func (m *MultiMap[K, V]) Clear() {
+	clear(m.items)
+	m.n = 0
+}
+
+func (m *MultiMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.items {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func (m *MultiMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.Items() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Items yields each key and value pair, so a key is yielded once per value.
+func (m *MultiMap[K, V]) Items() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, vs := range m.items {
+			for _, v := range vs {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Lists yields each key with all its values.
+func (m *MultiMap[K, V]) Lists() iter.Seq2[K, []V] {
+	return func(yield func(K, []V) bool) {
+		for k, vs := range m.items {
+			if !yield(k, vs) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	m := NewMultiMap[string, int]()
+	m.Add("a", 1, 2, 3)
+	m.Add("b", 4)
+	m.Add("c")
+	if m.Len() != 4 || m.KeyLen() != 2 || m.Contains("c") {
+		t.Errorf("Len() = %d, KeyLen() = %d", m.Len(), m.KeyLen())
+	}
+	if got := m.Get("a"); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Get(a) = %v", got)
+	}
+	if n := m.DeleteFunc("a", func(v int) bool { return v%2 == 1 }); n != 2 {
+		t.Errorf("DeleteFunc() = %d, want 2", n)
+	}
+	m.Set("b", 5, 6)
+	sum := 0
+	for _, v := range m.Items() {
+		sum += v
+	}
+	if sum != 13 || m.Len() != 3 {
+		t.Errorf("sum of Items() = %d, Len() = %d, want 13, 3", sum, m.Len())
+	}
+	m.DeleteFunc("a", func(int) bool { return true })
+	if m.Contains("a") || m.Len() != 2 {
+		t.Errorf("emptied key still present, Len() = %d", m.Len())
+	}
+	m.Clear()
+	if m.Len() != 0 || m.KeyLen() != 0 {
+		t.Error("Clear() left values")
+	}
+}
@@ -0,0 +1,125 @@
+package collections
+
+import (
+	"container/list"
+	"iter"
+)
+
+// OrderedSet is a set that remembers insertion order.
+// Adding an item that is already present keeps its position.
+type OrderedSet[T comparable] struct {
+	items map[T]*list.Element
+	order *list.List
+}
+
+func NewOrderedSet[T comparable]() *OrderedSet[T] {
+	return &OrderedSet[T]{items: make(map[T]*list.Element), order: list.New()}
+}
+
+func NewOrderedSetFromSlice[T comparable](items []T) *OrderedSet[T] {
+	set := NewOrderedSet[T]()
+	set.Add(items...)
+	return set
+}
+
+func (s *OrderedSet[T]) Add(items ...T) {
+	for _, item := range items {
+		if _, ok := s.items[item]; !ok {
+			s.items[item] = s.order.PushBack(item)
+		}
+	}
+}
+
+func (s *OrderedSet[T]) Remove(items ...T) {
+	for _, item := range items {
+		if el, ok := s.items[item]; ok {
+			s.order.Remove(el)
+			delete(s.items, item)
+		}
+	}
+}
+
+func (s *OrderedSet[T]) Contains(item T) bool {
+	_, ok := s.items[item]
+	return ok
+}
+
+func (s *OrderedSet[T]) Len() int {
+	return len(s.items)
+}
+
+func (s *OrderedSet[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+func (s *OrderedSet[T]) Clear() {
+	s.items = make(map[T]*list.Element)
+	s.order.Init()
+}
+
+// First returns the oldest item.
+func (s *OrderedSet[T]) First() (item T, ok bool) {
+	if el := s.order.Front(); el != nil {
+		return el.Value.(T), true
+	}
+	return item, false
+}
+
+// Last returns the newest item.
+func (s *OrderedSet[T]) Last() (item T, ok bool) {
+	if el := s.order.Back(); el != nil {
+		return el.Value.(T), true
+	}
+	return item, false
+}
+
+// Union keeps the order of s, followed by the new items of other in their order.
+func (s *OrderedSet[T]) Union(other SetLike[T]) *OrderedSet[T] {
+	result := NewOrderedSet[T]()
+	union(result, s, other)
+	return result
+}
+
+func (s *OrderedSet[T]) Intersection(other SetLike[T]) *OrderedSet[T] {
+	result := NewOrderedSet[T]()
+	intersection(result, s, other)
+	return result
+}
+
+func (s *OrderedSet[T]) Difference(other SetLike[T]) *OrderedSet[T] {
+	result := NewOrderedSet[T]()
+	difference(result, s, other)
+	return result
+}
+
+func (s *OrderedSet[T]) IsSubset(other SetLike[T]) bool {
+	return isSubset(s, other)
+}
+
+func (s *OrderedSet[T]) IsSuperset(other SetLike[T]) bool {
+	return isSubset(other, s)
+}
+
+func (s *OrderedSet[T]) IsDisjoint(other SetLike[T]) bool {
+	return isDisjoint(s, other)
+}
+
+// ToSlice returns the items in insertion order.
+func (s *OrderedSet[T]) ToSlice() []T {
+	slice := make([]T, 0, len(s.items))
+	for item := range s.ToSeq() {
+		slice = append(slice, item)
+	}
+	return slice
+}
+
+// ToSeq yields the items in insertion order.
+func (s *OrderedSet[T]) ToSeq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for el := s.order.Front(); el != nil; el = el.Next() {
+			if !yield(el.Value.(T)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrderedSet(t *testing.T) {
+	s := NewOrderedSetFromSlice([]string{"c", "a", "b", "a"})
+	if got := s.ToSlice(); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("ToSlice() = %v, want insertion order [c a b]", got)
+	}
+
+	s.Remove("a")
+	s.Add("a", "d")
+	if got := slices.Collect(s.ToSeq()); !slices.Equal(got, []string{"c", "b", "a", "d"}) {
+		t.Errorf("ToSeq() = %v, want [c b a d]", got)
+	}
+	if v, _ := s.First(); v != "c" {
+		t.Errorf("First() = %v, want c", v)
+	}
+	if v, _ := s.Last(); v != "d" {
+		t.Errorf("Last() = %v, want d", v)
+	}
+
+	other := NewSortedSetFromSlice([]string{"e", "d", "b"})
+	if got := s.Union(other).ToSlice(); !slices.Equal(got, []string{"c", "b", "a", "d", "e"}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := s.Intersection(other).ToSlice(); !slices.Equal(got, []string{"b", "d"}) {
+		t.Errorf("Intersection() = %v", got)
+	}
+	if got := s.Difference(other).ToSlice(); !slices.Equal(got, []string{"c", "a"}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if !s.IsSuperset(NewSetFromSlice([]string{"a", "b"})) || s.IsSubset(other) {
+		t.Error("comparisons mismatch")
+	}
+
+	s.Clear()
+	if _, ok := s.First(); ok || !s.IsEmpty() {
+		t.Error("Clear() left items")
+	}
+}
@@ -0,0 +1,151 @@
+package collections
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+)
+
+// PQItem is a handle to a value in a PriorityQueue, used to update its priority or remove it.
+type PQItem[T, P any] struct {
+	value    T
+	priority P
+	index    int // position in the heap, -1 once removed
+}
+
+func (it *PQItem[T, P]) Value() T    { return it.value }
+func (it *PQItem[T, P]) Priority() P { return it.priority }
+
+// PriorityQueue is a heap of values ordered by priority: Pop returns the value
+// whose priority is the least according to less.
+type PriorityQueue[T, P any] struct {
+	h pqHeap[T, P]
+}
+
+// NewPriorityQueue creates a PriorityQueue popping the least priority according to less first.
+func NewPriorityQueue[T, P any](less func(a, b P) bool) *PriorityQueue[T, P] {
+	return &PriorityQueue[T, P]{h: pqHeap[T, P]{less: less}}
+}
+
+// NewMinQueue creates a PriorityQueue popping the lowest priority first.
+func NewMinQueue[T any, P cmp.Ordered]() *PriorityQueue[T, P] {
+	return NewPriorityQueue[T](cmp.Less[P])
+}
+
+// NewMaxQueue creates a PriorityQueue popping the highest priority first.
+func NewMaxQueue[T any, P cmp.Ordered]() *PriorityQueue[T, P] {
+	return NewPriorityQueue[T](func(a, b P) bool { return cmp.Less(b, a) })
+}
+
+// Push adds value with priority and returns its handle.
+func (q *PriorityQueue[T, P]) Push(value T, priority P) *PQItem[T, P] {
+	it := &PQItem[T, P]{value: value, priority: priority}
+	heap.Push(&q.h, it)
+	return it
+}
+
+// Pop removes and returns the value with the least priority.
+func (q *PriorityQueue[T, P]) Pop() (value T, priority P, ok bool) {
+	if len(q.h.items) == 0 {
+		return value, priority, false
+	}
+	it := heap.Pop(&q.h).(*PQItem[T, P])
+	return it.value, it.priority, true
+}
+
+// Peek returns the value with the least priority without removing it.
+func (q *PriorityQueue[T, P]) Peek() (value T, priority P, ok bool) {
+	if len(q.h.items) == 0 {
+		return value, priority, false
+	}
+	it := q.h.items[0]
+	return it.value, it.priority, true
+}
+
+// Update changes the priority of item. It reports false if item is no longer in the queue.
+func (q *PriorityQueue[T, P]) Update(item *PQItem[T, P], priority P) bool {
+	if !q.has(item) {
+		return false
+	}
+	item.priority = priority
+	heap.Fix(&q.h, item.index)
+	return true
+}
+
+// Remove removes item from the queue. It reports false if item is no longer in the queue.
+func (q *PriorityQueue[T, P]) Remove(item *PQItem[T, P]) bool {
+	if !q.has(item) {
+		return false
+	}
+	heap.Remove(&q.h, item.index)
+	return true
+}
+
+func (q *PriorityQueue[T, P]) has(item *PQItem[T, P]) bool {
+	return item != nil && item.index >= 0 && item.index < len(q.h.items) && q.h.items[item.index] == item
+}
+
+func (q *PriorityQueue[T, P]) Len() int {
+	return len(q.h.items)
+}
+
+func (q *PriorityQueue[T, P]) IsEmpty() bool {
+	return len(q.h.items) == 0
+}
+
+func (q *PriorityQueue[T, P]) Clear() {
+	for _, it := range q.h.items {
+		it.index = -1
+	}
+	q.h.items = nil
+}
+
+// ToSeq yields the values and their priorities in heap order, not in priority order.
+// The queue must not be modified during the iteration.
+func (q *PriorityQueue[T, P]) ToSeq() iter.Seq2[T, P] {
+	return func(yield func(T, P) bool) {
+		for _, it := range q.h.items {
+			if !yield(it.value, it.priority) {
+				return
+			}
+		}
+	}
+}
+
+// Drain pops and yields the values in priority order until the queue is empty
+// or the iteration stops.
+func (q *PriorityQueue[T, P]) Drain() iter.Seq2[T, P] {
+	return func(yield func(T, P) bool) {
+		for {
+			v, p, ok := q.Pop()
+			if !ok || !yield(v, p) {
+				return
+			}
+		}
+	}
+}
+
+type pqHeap[T, P any] struct {
+	items []*PQItem[T, P]
+	less  func(a, b P) bool
+}
+
+func (h *pqHeap[T, P]) Len() int           { return len(h.items) }
+func (h *pqHeap[T, P]) Less(i, j int) bool { return h.less(h.items[i].priority, h.items[j].priority) }
+func (h *pqHeap[T, P]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+func (h *pqHeap[T, P]) Push(x any) {
+	it := x.(*PQItem[T, P])
+	it.index = len(h.items)
+	h.items = append(h.items, it)
+}
+func (h *pqHeap[T, P]) Pop() any {
+	it := h.items[len(h.items)-1]
+	h.items[len(h.items)-1] = nil
+	h.items = h.items[:len(h.items)-1]
+	it.index = -1
+	return it
+}
@@ -0,0 +1,57 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewMinQueue[string, int]()
+	q.Push("c", 3)
+	a := q.Push("a", 1)
+	b := q.Push("b", 2)
+	q.Push("d", 4)
+
+	if v, p, _ := q.Peek(); v != "a" || p != 1 {
+		t.Errorf("Peek() = %s:%d, want a:1", v, p)
+	}
+	if !q.Update(a, 5) {
+		t.Error("Update() = false, want true")
+	}
+	if !q.Remove(b) {
+		t.Error("Remove() = false, want true")
+	}
+	if q.Remove(b) {
+		t.Error("Remove() of a removed item = true, want false")
+	}
+	var got []string
+	for v := range q.Drain() {
+		got = append(got, v)
+	}
+	if want := []string{"c", "d", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Drain() = %v, want %v", got, want)
+	}
+	if q.Update(a, 0) {
+		t.Error("Update() of a popped item = true, want false")
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Error("Pop() on empty queue returned ok")
+	}
+}
+
+func TestMaxQueue(t *testing.T) {
+	q := NewMaxQueue[int, float64]()
+	for i, p := range []float64{0.5, 2.5, 1.5} {
+		q.Push(i, p)
+	}
+	if q.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", q.Len())
+	}
+	if v, _, _ := q.Pop(); v != 1 {
+		t.Errorf("Pop() = %d, want 1", v)
+	}
+	q.Clear()
+	if !q.IsEmpty() {
+		t.Error("IsEmpty() after Clear = false")
+	}
+}
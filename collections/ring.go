@@ -0,0 +1,105 @@
+package collections
+
+import "iter"
+
+// Ring is a fixed-capacity FIFO buffer. Pushing to a full ring overwrites the oldest value,
+// which makes it suited to keeping the last n events, prices or log lines.
+type Ring[T any] struct {
+	buf  []T
+	head int // index of the oldest value
+	n    int
+}
+
+// NewRing creates a Ring holding up to capacity values, at least 1.
+func NewRing[T any](capacity int) *Ring[T] {
+	return &Ring[T]{buf: make([]T, max(capacity, 1))}
+}
+
+// Push appends value. If the ring is full the oldest value is overwritten and returned.
+func (r *Ring[T]) Push(value T) (evicted T, ok bool) {
+	if r.n == len(r.buf) {
+		evicted, ok = r.buf[r.head], true
+		r.buf[r.head] = value
+		r.head = (r.head + 1) % len(r.buf)
+		return evicted, ok
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = value
+	r.n++
+	return evicted, false
+}
+
+// Pop removes and returns the oldest value.
+func (r *Ring[T]) Pop() (value T, ok bool) {
+	if r.n == 0 {
+		return value, false
+	}
+	var zero T
+	value, r.buf[r.head] = r.buf[r.head], zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+	return value, true
+}
+
+// Oldest returns the oldest value without removing it.
+func (r *Ring[T]) Oldest() (value T, ok bool) {
+	if r.n == 0 {
+		return value, false
+	}
+	return r.buf[r.head], true
+}
+
+// Newest returns the most recently pushed value.
+func (r *Ring[T]) Newest() (value T, ok bool) {
+	if r.n == 0 {
+		return value, false
+	}
+	return r.buf[(r.head+r.n-1)%len(r.buf)], true
+}
+
+// At returns the i-th value from the oldest. It panics if i is out of range.
+func (r *Ring[T]) At(i int) T {
+	if i < 0 || i >= r.n {
+		panic("collections: Ring index out of range")
+	}
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+func (r *Ring[T]) Len() int {
+	return r.n
+}
+
+func (r *Ring[T]) Cap() int {
+	return len(r.buf)
+}
+
+func (r *Ring[T]) IsFull() bool {
+	return r.n == len(r.buf)
+}
+
+func (r *Ring[T]) IsEmpty() bool {
+	return r.n == 0
+}
+
+func (r *Ring[T]) Clear() {
+	clear(r.buf)
+	r.head, r.n = 0, 0
+}
+
+// ToSeq yields the values from the oldest to the newest.
+func (r *Ring[T]) ToSeq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < r.n; i++ {
+			if !yield(r.buf[(r.head+i)%len(r.buf)]) {
+				return
+			}
+		}
+	}
+}
+
+func (r *Ring[T]) ToSlice() []T {
+	rv := make([]T, 0, r.n)
+	for v := range r.ToSeq() {
+		rv = append(rv, v)
+	}
+	return rv
+}
@@ -0,0 +1,37 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](3)
+	for i := 1; i <= 3; i++ {
+		if _, ok := r.Push(i); ok {
+			t.Errorf("Push(%d) evicted before the ring was full", i)
+		}
+	}
+	if !r.IsFull() {
+		t.Error("IsFull() = false, want true")
+	}
+	if v, ok := r.Push(4); !ok || v != 1 {
+		t.Errorf("Push(4) evicted %d, %v, want 1, true", v, ok)
+	}
+	if got, want := r.ToSlice(), []int{2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+	if v, _ := r.Newest(); v != 4 {
+		t.Errorf("Newest() = %d, want 4", v)
+	}
+	if v, _ := r.Pop(); v != 2 {
+		t.Errorf("Pop() = %d, want 2", v)
+	}
+	if v, _ := r.Oldest(); v != 3 || r.At(1) != 4 {
+		t.Errorf("Oldest() = %d, want 3", v)
+	}
+	r.Clear()
+	if !r.IsEmpty() || r.Cap() != 3 {
+		t.Errorf("after Clear Len() = %d, Cap() = %d", r.Len(), r.Cap())
+	}
+}
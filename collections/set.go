@@ -4,6 +4,24 @@ import (
 	"iter"
 )
 
+// SetLike is the read side shared by all the sets of this package,
+// so that Union, Intersection, Difference and the comparisons work across implementations.
+type SetLike[T comparable] interface {
+	Contains(item T) bool
+	Len() int
+	ToSeq() iter.Seq[T]
+}
+
+// MutableSet is the common interface of Set, SyncSet, OrderedSet and SortedSet.
+type MutableSet[T comparable] interface {
+	SetLike[T]
+	Add(items ...T)
+	Remove(items ...T)
+	Clear()
+	IsEmpty() bool
+	ToSlice() []T
+}
+
 // Set is a collection of unique items.
 type Set[T comparable] struct {
 	items map[T]struct{}
@@ -20,7 +38,7 @@ func NewSetFromSlice[T comparable](items []T) *Set[T] {
 }
 
 func (s *Set[T]) Add(items ...T) {
-	for _, item := range items {	
+	for _, item := range items {
 		s.items[item] = struct{}{}
 	}
 }
@@ -48,48 +66,35 @@ func (s *Set[T]) Clear() {
 	s.items = make(map[T]struct{})
 }
 
-func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+func (s *Set[T]) Union(other SetLike[T]) *Set[T] {
 	result := NewSet[T]()
-	for item := range s.items {
-		result.Add(item)
-	}
-	for item := range other.items {
-		result.Add(item)
-	}
+	union(result, s, other)
 	return result
 }
 
-func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+func (s *Set[T]) Intersection(other SetLike[T]) *Set[T] {
 	result := NewSet[T]()
-	for item := range s.items {
-		if other.Contains(item) {
-			result.Add(item)
-		}
-	}
+	intersection(result, s, other)
 	return result
 }
 
-func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+func (s *Set[T]) Difference(other SetLike[T]) *Set[T] {
 	result := NewSet[T]()
-	for item := range s.items {
-		if !other.Contains(item) {
-			result.Add(item)
-		}
-	}
+	difference(result, s, other)
 	return result
 }
 
-func (s *Set[T]) IsSubset(other *Set[T]) bool {
-	return s.Intersection(other).Len() == s.Len()
+func (s *Set[T]) IsSubset(other SetLike[T]) bool {
+	return isSubset(s, other)
 }
 
-func (s *Set[T]) IsSuperset(other *Set[T]) bool {
-	return other.IsSubset(s)
+func (s *Set[T]) IsSuperset(other SetLike[T]) bool {
+	return isSubset(other, s)
 }
 
-func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
-	return s.Intersection(other).IsEmpty()
-}	
+func (s *Set[T]) IsDisjoint(other SetLike[T]) bool {
+	return isDisjoint(s, other)
+}
 
 func (s *Set[T]) ToSlice() []T {
 	slice := make([]T, 0, len(s.items))
@@ -109,3 +114,48 @@ func (s *Set[T]) ToSeq() iter.Seq[T] {
 	}
 }
 
+func union[T comparable](dst MutableSet[T], a, b SetLike[T]) {
+	for item := range a.ToSeq() {
+		dst.Add(item)
+	}
+	for item := range b.ToSeq() {
+		dst.Add(item)
+	}
+}
+
+func intersection[T comparable](dst MutableSet[T], a, b SetLike[T]) {
+	for item := range a.ToSeq() {
+		if b.Contains(item) {
+			dst.Add(item)
+		}
+	}
+}
+
+func difference[T comparable](dst MutableSet[T], a, b SetLike[T]) {
+	for item := range a.ToSeq() {
+		if !b.Contains(item) {
+			dst.Add(item)
+		}
+	}
+}
+
+func isSubset[T comparable](a, b SetLike[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	for item := range a.ToSeq() {
+		if !b.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDisjoint[T comparable](a, b SetLike[T]) bool {
+	for item := range a.ToSeq() {
+		if b.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
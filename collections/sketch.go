@@ -0,0 +1,114 @@
+package collections
+
+import (
+	"errors"
+	"hash/maphash"
+	"iter"
+	"math"
+)
+
+// CountMinSketch estimates the counts of a stream in bounded memory.
+// Estimates never undercount, and overcount by at most epsilon * Total()
+// with probability 1 - delta.
+type CountMinSketch[T comparable] struct {
+	seed  maphash.Seed
+	width uint64
+	depth int
+	table []int64 // depth rows of width counters
+	total int64
+}
+
+// NewCountMinSketch creates a sketch with error factor epsilon and failure probability delta,
+// using ceil(e/epsilon) * ceil(ln(1/delta)) counters. epsilon is clamped to [1e-6, 1]
+// and delta to [1e-12, 0.5], so that the table stays finite.
+func NewCountMinSketch[T comparable](epsilon, delta float64) *CountMinSketch[T] {
+	epsilon = clampRate(epsilon, 1e-6, 1)
+	delta = clampRate(delta, 1e-12, 0.5)
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return &CountMinSketch[T]{
+		seed:  maphash.MakeSeed(),
+		width: max(width, 1),
+		depth: max(depth, 1),
+		table: make([]int64, max(width, 1)*uint64(max(depth, 1))),
+	}
+}
+
+// clampRate bounds a rate or probability parameter to [lo, hi], taking hi for NaN.
+func clampRate(v, lo, hi float64) float64 {
+	if math.IsNaN(v) {
+		return hi
+	}
+	return min(max(v, lo), hi)
+}
+
+// cells yields the index of the counter of value in each row, using double hashing.
+func (s *CountMinSketch[T]) cells(value T) iter.Seq[int] {
+	h := maphash.Comparable(s.seed, value)
+	h1, h2 := h&0xffffffff, h>>32|1
+	return func(yield func(int) bool) {
+		for i := 0; i < s.depth; i++ {
+			col := (h1 + uint64(i)*h2) % s.width
+			if !yield(i*int(s.width) + int(col)) {
+				return
+			}
+		}
+	}
+}
+
+func (s *CountMinSketch[T]) Add(value T) {
+	s.AddN(value, 1)
+}
+
+func (s *CountMinSketch[T]) AddAll(values ...T) {
+	for _, value := range values {
+		s.AddN(value, 1)
+	}
+}
+
+// AddN adds n occurrences of value. n must not be negative.
+func (s *CountMinSketch[T]) AddN(value T, n int64) {
+	for i := range s.cells(value) {
+		s.table[i] += n
+	}
+	s.total += n
+}
+
+// Get returns the estimated count of value.
+func (s *CountMinSketch[T]) Get(value T) int64 {
+	est := int64(math.MaxInt64)
+	for i := range s.cells(value) {
+		est = min(est, s.table[i])
+	}
+	return est
+}
+
+// Total returns the number of values added.
+func (s *CountMinSketch[T]) Total() int64 {
+	return s.total
+}
+
+// Merge adds the counts of other, which must derive from s through Copy
+// so that both hash values the same way.
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if s.seed != other.seed || s.width != other.width || s.depth != other.depth {
+		return errors.New("count-min sketches are not compatible")
+	}
+	for i, n := range other.table {
+		s.table[i] += n
+	}
+	s.total += other.total
+	return nil
+}
+
+// Copy returns an independent sketch with the same parameters and counts.
+func (s *CountMinSketch[T]) Copy() *CountMinSketch[T] {
+	rv := *s
+	rv.table = append([]int64(nil), s.table...)
+	return &rv
+}
+
+func (s *CountMinSketch[T]) Clear() {
+	clear(s.table)
+	s.total = 0
+}
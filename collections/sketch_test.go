@@ -0,0 +1,92 @@
+package collections
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	s := NewCountMinSketch[string](0.001, 0.01)
+	exact := NewCounter[string]()
+	for i := 0; i < 10000; i++ {
+		v := strconv.Itoa(i % 500)
+		if i%10 == 0 {
+			v = "hot"
+		}
+		s.Add(v)
+		exact.Add(v)
+	}
+	if s.Total() != 10000 {
+		t.Errorf("Total() = %d, want 10000", s.Total())
+	}
+	bound := int64(0.001 * 10000)
+	for k, n := range exact.Items() {
+		est := s.Get(k)
+		if est < n {
+			t.Fatalf("Get(%s) = %d undercounts %d", k, est, n)
+		}
+		if est-n > bound*5 {
+			t.Errorf("Get(%s) = %d, exact %d, error above %d", k, est, n, bound*5)
+		}
+	}
+
+	other := s.Copy()
+	other.AddN("hot", 10)
+	if err := s.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if got := s.Get("hot"); got < 2*1000+10 {
+		t.Errorf("Get(hot) after Merge() = %d", got)
+	}
+	if err := s.Merge(NewCountMinSketch[string](0.001, 0.01)); err == nil {
+		t.Error("Merge() of an unrelated sketch expected error")
+	}
+	s.Clear()
+	if s.Get("hot") != 0 || s.Total() != 0 {
+		t.Error("Clear() left counts")
+	}
+}
+
+func TestCountMinSketch_InvalidParams(t *testing.T) {
+	for _, p := range [][2]float64{{0, 0.01}, {-1, 0.01}, {math.NaN(), 0.01}, {0.01, 0}, {0.01, 1}, {0.01, 2}, {0.01, math.NaN()}, {math.Inf(1), -1}} {
+		s := NewCountMinSketch[string](p[0], p[1])
+		s.Add("a")
+		if s.Get("a") != 1 || s.width < 1 || s.depth < 1 || s.width > 3e6 {
+			t.Errorf("NewCountMinSketch(%v, %v): width = %d, depth = %d, Get(a) = %d", p[0], p[1], s.width, s.depth, s.Get("a"))
+		}
+	}
+}
+
+func TestSpaceSaving(t *testing.T) {
+	s := NewSpaceSaving[string](10)
+	for i := 0; i < 10000; i++ {
+		switch {
+		case i%4 == 0:
+			s.Add("a")
+		case i%10 == 1:
+			s.Add("b")
+		default:
+			s.Add("noise" + strconv.Itoa(i))
+		}
+	}
+	if s.Len() != 10 {
+		t.Errorf("Len() = %d, want capacity 10", s.Len())
+	}
+	var top []string
+	for k, n := range s.MostCommon(2) {
+		if n-s.Error(k) > s.Get(k) {
+			t.Errorf("inconsistent count for %s", k)
+		}
+		top = append(top, k)
+	}
+	if len(top) != 2 || top[0] != "a" || top[1] != "b" {
+		t.Errorf("MostCommon(2) = %v, want [a b]", top)
+	}
+	if lo := s.Get("a") - s.Error("a"); lo > 2500 || s.Get("a") < 2500 {
+		t.Errorf("Get(a) = %d with error %d does not bound 2500", s.Get("a"), s.Error("a"))
+	}
+	if s.Get("missing") != 0 || s.Total() != 10000 {
+		t.Errorf("Get(missing) = %d, Total() = %d", s.Get("missing"), s.Total())
+	}
+}
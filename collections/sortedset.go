@@ -0,0 +1,150 @@
+package collections
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// SortedSet is a set kept in ascending order, backed by a sorted slice.
+// Lookups and range queries are O(log n), Add and Remove are O(n).
+type SortedSet[T cmp.Ordered] struct {
+	items []T
+}
+
+func NewSortedSet[T cmp.Ordered]() *SortedSet[T] {
+	return &SortedSet[T]{}
+}
+
+func NewSortedSetFromSlice[T cmp.Ordered](items []T) *SortedSet[T] {
+	sorted := slices.Clone(items)
+	slices.Sort(sorted)
+	return &SortedSet[T]{items: slices.Compact(sorted)}
+}
+
+func (s *SortedSet[T]) Add(items ...T) {
+	for _, item := range items {
+		if i, found := slices.BinarySearch(s.items, item); !found {
+			s.items = slices.Insert(s.items, i, item)
+		}
+	}
+}
+
+func (s *SortedSet[T]) Remove(items ...T) {
+	for _, item := range items {
+		if i, found := slices.BinarySearch(s.items, item); found {
+			s.items = slices.Delete(s.items, i, i+1)
+		}
+	}
+}
+
+func (s *SortedSet[T]) Contains(item T) bool {
+	_, found := slices.BinarySearch(s.items, item)
+	return found
+}
+
+func (s *SortedSet[T]) Len() int {
+	return len(s.items)
+}
+
+func (s *SortedSet[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+func (s *SortedSet[T]) Clear() {
+	s.items = nil
+}
+
+// At returns the i-th smallest item.
+func (s *SortedSet[T]) At(i int) T {
+	return s.items[i]
+}
+
+// Min returns the smallest item.
+func (s *SortedSet[T]) Min() (item T, ok bool) {
+	if len(s.items) == 0 {
+		return item, false
+	}
+	return s.items[0], true
+}
+
+// Max returns the largest item.
+func (s *SortedSet[T]) Max() (item T, ok bool) {
+	if len(s.items) == 0 {
+		return item, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Floor returns the largest item <= x.
+func (s *SortedSet[T]) Floor(x T) (item T, ok bool) {
+	i, found := slices.BinarySearch(s.items, x)
+	if found {
+		return s.items[i], true
+	}
+	if i == 0 {
+		return item, false
+	}
+	return s.items[i-1], true
+}
+
+// Ceil returns the smallest item >= x.
+func (s *SortedSet[T]) Ceil(x T) (item T, ok bool) {
+	i, _ := slices.BinarySearch(s.items, x)
+	if i == len(s.items) {
+		return item, false
+	}
+	return s.items[i], true
+}
+
+// Range yields the items in [lo, hi] in ascending order.
+func (s *SortedSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i, _ := slices.BinarySearch(s.items, lo)
+		for ; i < len(s.items) && s.items[i] <= hi; i++ {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SortedSet[T]) Union(other SetLike[T]) *SortedSet[T] {
+	items := make([]T, 0, s.Len()+other.Len())
+	items = append(items, s.items...)
+	return NewSortedSetFromSlice(slices.AppendSeq(items, other.ToSeq()))
+}
+
+func (s *SortedSet[T]) Intersection(other SetLike[T]) *SortedSet[T] {
+	result := NewSortedSet[T]()
+	intersection(result, s, other)
+	return result
+}
+
+func (s *SortedSet[T]) Difference(other SetLike[T]) *SortedSet[T] {
+	result := NewSortedSet[T]()
+	difference(result, s, other)
+	return result
+}
+
+func (s *SortedSet[T]) IsSubset(other SetLike[T]) bool {
+	return isSubset(s, other)
+}
+
+func (s *SortedSet[T]) IsSuperset(other SetLike[T]) bool {
+	return isSubset(other, s)
+}
+
+func (s *SortedSet[T]) IsDisjoint(other SetLike[T]) bool {
+	return isDisjoint(s, other)
+}
+
+// ToSlice returns the items in ascending order.
+func (s *SortedSet[T]) ToSlice() []T {
+	return slices.Clone(s.items)
+}
+
+// ToSeq yields the items in ascending order.
+func (s *SortedSet[T]) ToSeq() iter.Seq[T] {
+	return slices.Values(s.items)
+}
@@ -0,0 +1,77 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	s := NewSortedSetFromSlice([]int{5, 1, 9, 3, 5})
+	s.Add(7, 3)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("ToSlice() = %v", got)
+	}
+	s.Remove(1, 4)
+	if s.Len() != 4 || s.Contains(1) || !s.Contains(9) {
+		t.Errorf("Remove() left %v", s.ToSlice())
+	}
+	if v, _ := s.Min(); v != 3 {
+		t.Errorf("Min() = %d, want 3", v)
+	}
+	if v, _ := s.Max(); v != 9 {
+		t.Errorf("Max() = %d, want 9", v)
+	}
+	if s.At(1) != 5 {
+		t.Errorf("At(1) = %d, want 5", s.At(1))
+	}
+}
+
+func TestSortedSet_Queries(t *testing.T) {
+	s := NewSortedSetFromSlice([]int{10, 20, 30, 40})
+	tests := []struct {
+		x         int
+		floor     int
+		floorOK   bool
+		ceil      int
+		ceilOK    bool
+		rangeHigh int
+		want      []int
+	}{
+		{x: 5, floorOK: false, ceil: 10, ceilOK: true, rangeHigh: 25, want: []int{10, 20}},
+		{x: 20, floor: 20, floorOK: true, ceil: 20, ceilOK: true, rangeHigh: 40, want: []int{20, 30, 40}},
+		{x: 25, floor: 20, floorOK: true, ceil: 30, ceilOK: true, rangeHigh: 29, want: nil},
+		{x: 45, floor: 40, floorOK: true, ceilOK: false, rangeHigh: 50, want: nil},
+	}
+	for _, tt := range tests {
+		if v, ok := s.Floor(tt.x); ok != tt.floorOK || (ok && v != tt.floor) {
+			t.Errorf("Floor(%d) = %d, %v, want %d, %v", tt.x, v, ok, tt.floor, tt.floorOK)
+		}
+		if v, ok := s.Ceil(tt.x); ok != tt.ceilOK || (ok && v != tt.ceil) {
+			t.Errorf("Ceil(%d) = %d, %v, want %d, %v", tt.x, v, ok, tt.ceil, tt.ceilOK)
+		}
+		if got := slices.Collect(s.Range(tt.x, tt.rangeHigh)); !slices.Equal(got, tt.want) {
+			t.Errorf("Range(%d, %d) = %v, want %v", tt.x, tt.rangeHigh, got, tt.want)
+		}
+	}
+}
+
+func TestSortedSet_Algebra(t *testing.T) {
+	s := NewSortedSetFromSlice([]int{1, 2, 3})
+	other := NewSetFromSlice([]int{4, 3, 2})
+	if got := s.Union(other).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := s.Intersection(other).ToSlice(); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Intersection() = %v", got)
+	}
+	if got := s.Difference(other).ToSlice(); !slices.Equal(got, []int{1}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	// the plain Set accepts any implementation too
+	if got := slices.Sorted(other.Intersection(s).ToSeq()); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Set.Intersection(SortedSet) = %v", got)
+	}
+	if s.IsSubset(other) || s.IsDisjoint(other) || !s.IsSuperset(NewSortedSetFromSlice([]int{1})) {
+		t.Error("comparisons mismatch")
+	}
+}
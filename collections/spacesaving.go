@@ -0,0 +1,133 @@
+package collections
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+	"slices"
+)
+
+type ssItem[T comparable] struct {
+	value T
+	count int64
+	err   int64 // maximum overestimation of count
+	index int   // position in the heap
+}
+
+type ssHeap[T comparable] []*ssItem[T]
+
+func (h ssHeap[T]) Len() int           { return len(h) }
+func (h ssHeap[T]) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h ssHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *ssHeap[T]) Push(x any) {
+	it := x.(*ssItem[T])
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+func (h *ssHeap[T]) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// SpaceSaving finds the heavy hitters of a stream while tracking at most capacity values,
+// with the Space-Saving algorithm. Any value occurring more than Total()/capacity times
+// is guaranteed to be tracked, and the count of a tracked value is overestimated by at
+// most its Error.
+type SpaceSaving[T comparable] struct {
+	capacity int
+	items    map[T]*ssItem[T]
+	heap     ssHeap[T] // min-heap by count
+	total    int64
+}
+
+// NewSpaceSaving creates a SpaceSaving tracking at most capacity values.
+func NewSpaceSaving[T comparable](capacity int) *SpaceSaving[T] {
+	capacity = max(capacity, 1)
+	return &SpaceSaving[T]{capacity: capacity, items: make(map[T]*ssItem[T], capacity)}
+}
+
+func (s *SpaceSaving[T]) Add(value T) {
+	s.AddN(value, 1)
+}
+
+func (s *SpaceSaving[T]) AddAll(values ...T) {
+	for _, value := range values {
+		s.AddN(value, 1)
+	}
+}
+
+// AddN adds n occurrences of value. n must not be negative.
+func (s *SpaceSaving[T]) AddN(value T, n int64) {
+	s.total += n
+	if it, ok := s.items[value]; ok {
+		it.count += n
+		heap.Fix(&s.heap, it.index)
+		return
+	}
+	if len(s.heap) < s.capacity {
+		it := &ssItem[T]{value: value, count: n}
+		s.items[value] = it
+		heap.Push(&s.heap, it)
+		return
+	}
+	// replace the value with the lowest count, inheriting its count as error
+	it := s.heap[0]
+	delete(s.items, it.value)
+	it.value, it.err, it.count = value, it.count, it.count+n
+	s.items[value] = it
+	heap.Fix(&s.heap, 0)
+}
+
+// Get returns the estimated count of value, 0 if it is not tracked.
+func (s *SpaceSaving[T]) Get(value T) int64 {
+	if it, ok := s.items[value]; ok {
+		return it.count
+	}
+	return 0
+}
+
+// Error returns the maximum overestimation of the count of value.
+func (s *SpaceSaving[T]) Error(value T) int64 {
+	if it, ok := s.items[value]; ok {
+		return it.err
+	}
+	return 0
+}
+
+// Len returns the number of tracked values.
+func (s *SpaceSaving[T]) Len() int {
+	return len(s.items)
+}
+
+// Total returns the number of values added.
+func (s *SpaceSaving[T]) Total() int64 {
+	return s.total
+}
+
+// MostCommon yields the n tracked values with the highest estimated counts, most common first.
+func (s *SpaceSaving[T]) MostCommon(n int) iter.Seq2[T, int64] {
+	items := slices.Clone(s.heap)
+	slices.SortFunc(items, func(a, b *ssItem[T]) int {
+		return cmp.Compare(b.count, a.count)
+	})
+	items = items[:max(min(n, len(items)), 0)]
+	return func(yield func(T, int64) bool) {
+		for _, it := range items {
+			if !yield(it.value, it.count) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SpaceSaving[T]) Clear() {
+	s.items = make(map[T]*ssItem[T], s.capacity)
+	s.heap = nil
+	s.total = 0
+}
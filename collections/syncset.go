@@ -0,0 +1,113 @@
+package collections
+
+import (
+	"iter"
+	"sync"
+)
+
+// SyncSet is a Set safe for concurrent use.
+// Iteration works on a snapshot, so the set may be modified while iterating.
+type SyncSet[T comparable] struct {
+	mu  sync.RWMutex
+	set *Set[T]
+}
+
+func NewSyncSet[T comparable]() *SyncSet[T] {
+	return &SyncSet[T]{set: NewSet[T]()}
+}
+
+func NewSyncSetFromSlice[T comparable](items []T) *SyncSet[T] {
+	return &SyncSet[T]{set: NewSetFromSlice(items)}
+}
+
+func (s *SyncSet[T]) Add(items ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set.Add(items...)
+}
+
+// AddIfAbsent adds item and reports whether it was absent, atomically.
+func (s *SyncSet[T]) AddIfAbsent(item T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.set.Contains(item) {
+		return false
+	}
+	s.set.Add(item)
+	return true
+}
+
+func (s *SyncSet[T]) Remove(items ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set.Remove(items...)
+}
+
+func (s *SyncSet[T]) Contains(item T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Contains(item)
+}
+
+func (s *SyncSet[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Len()
+}
+
+func (s *SyncSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+func (s *SyncSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set.Clear()
+}
+
+func (s *SyncSet[T]) Union(other SetLike[T]) *SyncSet[T] {
+	result := NewSyncSet[T]()
+	union(result, s, other)
+	return result
+}
+
+func (s *SyncSet[T]) Intersection(other SetLike[T]) *SyncSet[T] {
+	result := NewSyncSet[T]()
+	intersection(result, s, other)
+	return result
+}
+
+func (s *SyncSet[T]) Difference(other SetLike[T]) *SyncSet[T] {
+	result := NewSyncSet[T]()
+	difference(result, s, other)
+	return result
+}
+
+func (s *SyncSet[T]) IsSubset(other SetLike[T]) bool {
+	return isSubset(s, other)
+}
+
+func (s *SyncSet[T]) IsSuperset(other SetLike[T]) bool {
+	return isSubset(other, s)
+}
+
+func (s *SyncSet[T]) IsDisjoint(other SetLike[T]) bool {
+	return isDisjoint(s, other)
+}
+
+func (s *SyncSet[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.ToSlice()
+}
+
+// ToSeq yields the items of a snapshot taken when the iteration starts.
+func (s *SyncSet[T]) ToSeq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range s.ToSlice() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+var (
+	_ MutableSet[int] = (*Set[int])(nil)
+	_ MutableSet[int] = (*SyncSet[int])(nil)
+	_ MutableSet[int] = (*OrderedSet[int])(nil)
+	_ MutableSet[int] = (*SortedSet[int])(nil)
+)
+
+func TestSyncSet_Concurrent(t *testing.T) {
+	s := NewSyncSet[int]()
+	var wg sync.WaitGroup
+	var added sync.Map
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if s.AddIfAbsent(j) {
+					if _, dup := added.LoadOrStore(j, i); dup {
+						t.Errorf("AddIfAbsent(%d) succeeded twice", j)
+					}
+				}
+				for range s.ToSeq() {
+					s.Contains(j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 100 {
+		t.Errorf("Len() = %d, want 100", s.Len())
+	}
+}
+
+func TestSyncSet_Algebra(t *testing.T) {
+	s := NewSyncSetFromSlice([]string{"a", "b", "c"})
+	other := NewSetFromSlice([]string{"b", "c", "d"})
+
+	if got := slices.Sorted(s.Union(other).ToSeq()); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := slices.Sorted(s.Intersection(other).ToSeq()); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("Intersection() = %v", got)
+	}
+	if got := slices.Sorted(s.Difference(other).ToSeq()); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if !s.Union(s).IsSuperset(s) || s.IsDisjoint(other) {
+		t.Error("comparisons mismatch")
+	}
+	s.Remove("a")
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Error("IsEmpty() after Clear() = false")
+	}
+}
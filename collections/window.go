@@ -0,0 +1,114 @@
+package collections
+
+import (
+	"sync"
+	"time"
+)
+
+type windowBucket[T comparable] struct {
+	start int64 // start of the bucket in units of the bucket width since the epoch
+	items map[T]int64
+}
+
+// WindowCounter counts values over a sliding time window, e.g. the last 5 minutes.
+// The window is split into buckets that are recycled as time passes, so memory
+// is bounded by the number of distinct values seen within the window.
+// It is safe for concurrent use.
+type WindowCounter[T comparable] struct {
+	mu      sync.Mutex
+	width   time.Duration
+	buckets []windowBucket[T]
+	now     func() time.Time
+}
+
+// NewWindowCounter creates a WindowCounter over window, split into n buckets.
+// Counts expire with a granularity of window / n.
+func NewWindowCounter[T comparable](window time.Duration, n int) *WindowCounter[T] {
+	n = max(n, 1)
+	return &WindowCounter[T]{
+		width:   max(window/time.Duration(n), 1),
+		buckets: make([]windowBucket[T], n),
+		now:     time.Now,
+	}
+}
+
+// Window returns the covered duration.
+func (w *WindowCounter[T]) Window() time.Duration {
+	return w.width * time.Duration(len(w.buckets))
+}
+
+func (w *WindowCounter[T]) Add(value T) {
+	w.AddN(value, 1)
+}
+
+func (w *WindowCounter[T]) AddN(value T, n int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	slot := w.now().UnixNano() / int64(w.width)
+	b := &w.buckets[slot%int64(len(w.buckets))]
+	if b.start != slot || b.items == nil {
+		b.start = slot
+		b.items = make(map[T]int64)
+	}
+	b.items[value] += n
+}
+
+// live calls f for each bucket inside the window. It must be called with w.mu held.
+func (w *WindowCounter[T]) live(f func(b *windowBucket[T])) {
+	slot := w.now().UnixNano() / int64(w.width)
+	for i := range w.buckets {
+		b := &w.buckets[i]
+		if b.items != nil && slot-b.start < int64(len(w.buckets)) {
+			f(b)
+		}
+	}
+}
+
+// Get returns the count of value within the window.
+func (w *WindowCounter[T]) Get(value T) int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var n int64
+	w.live(func(b *windowBucket[T]) {
+		n += b.items[value]
+	})
+	return n
+}
+
+// Total returns the sum of all the counts within the window.
+func (w *WindowCounter[T]) Total() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var n int64
+	w.live(func(b *windowBucket[T]) {
+		for _, v := range b.items {
+			n += v
+		}
+	})
+	return n
+}
+
+// Rate returns the average number of occurrences of value per second within the window.
+func (w *WindowCounter[T]) Rate(value T) float64 {
+	return float64(w.Get(value)) / w.Window().Seconds()
+}
+
+// Snapshot returns the counts within the window as a Counter,
+// e.g. to get the most common values with MostCommon.
+func (w *WindowCounter[T]) Snapshot() *Counter[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rv := NewCounter[T]()
+	w.live(func(b *windowBucket[T]) {
+		for k, v := range b.items {
+			rv.AddN(k, v)
+		}
+	})
+	return rv
+}
+
+func (w *WindowCounter[T]) Clear() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	clear(w.buckets)
+}
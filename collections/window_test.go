@@ -0,0 +1,67 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWindowCounter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w := NewWindowCounter[string](time.Minute, 6)
+	w.now = func() time.Time { return now }
+
+	w.AddN("a", 2)
+	w.Add("b")
+	now = now.Add(30 * time.Second)
+	w.Add("a")
+	if got := w.Get("a"); got != 3 {
+		t.Errorf("Get(a) = %d, want 3", got)
+	}
+	if got := w.Total(); got != 4 {
+		t.Errorf("Total() = %d, want 4", got)
+	}
+
+	// the first bucket leaves the window
+	now = now.Add(35 * time.Second)
+	if got := w.Get("a"); got != 1 {
+		t.Errorf("Get(a) after expiry = %d, want 1", got)
+	}
+	if got := w.Get("b"); got != 0 {
+		t.Errorf("Get(b) after expiry = %d, want 0", got)
+	}
+	for k, n := range w.Snapshot().MostCommon(1) {
+		if k != "a" || n != 1 {
+			t.Errorf("MostCommon(1) = %s:%d, want a:1", k, n)
+		}
+	}
+
+	// a recycled bucket drops its old counts
+	now = now.Add(time.Hour)
+	w.Add("c")
+	if got := w.Total(); got != 1 {
+		t.Errorf("Total() after an hour = %d, want 1", got)
+	}
+	w.Clear()
+	if got := w.Total(); got != 0 {
+		t.Errorf("Total() after Clear = %d, want 0", got)
+	}
+}
+
+func TestWindowCounter_Concurrent(t *testing.T) {
+	w := NewWindowCounter[int](time.Hour, 4)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				w.Add(j % 10)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := w.Total(); got != 8000 {
+		t.Errorf("Total() = %d, want 8000", got)
+	}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyan/wheels/internal/reflectx"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// leaf is a configurable value of a struct: a field that is not a nested struct.
+type leaf struct {
+	path  string // dotted key path, e.g. mysql.host
+	names []string
+	field reflect.StructField
+	index []int
+}
+
+// keyName returns the key of a field: its toml, yaml or json tag name,
+// or its name lowercased. It returns "" for skipped fields.
+func keyName(sf reflect.StructField, tags ...string) string {
+	if len(tags) == 0 {
+		tags = []string{"toml", "yaml", "json"}
+	}
+	for _, t := range tags {
+		if v, ok := sf.Tag.Lookup(t); ok {
+			name, _, _ := strings.Cut(v, ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return strings.ToLower(sf.Name)
+}
+
+func hasKeyTag(sf reflect.StructField) bool {
+	for _, t := range []string{"toml", "yaml", "json"} {
+		if v, ok := sf.Tag.Lookup(t); ok {
+			if name, _, _ := strings.Cut(v, ","); name != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNested reports whether t is a struct whose fields are configured one by one,
+// rather than a value decoding itself like time.Time or types implementing TextUnmarshaler.
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// leaves lists the configurable values of the struct type t.
+func leaves(t reflect.Type) []leaf {
+	var rv []leaf
+	var walk func(t reflect.Type, names []string, index []int)
+	walk = func(t reflect.Type, names []string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			idx := append(append([]int(nil), index...), i)
+			// untagged embedded structs are flattened, as by go-toml and encoding/json
+			// (yaml needs the inline option for the same layout)
+			if sf.Anonymous && isNested(sf.Type) && !hasKeyTag(sf) {
+				walk(derefType(sf.Type), names, idx)
+				continue
+			}
+			name := keyName(sf)
+			if name == "" {
+				continue
+			}
+			ns := append(append([]string(nil), names...), name)
+			if isNested(sf.Type) {
+				walk(derefType(sf.Type), ns, idx)
+				continue
+			}
+			rv = append(rv, leaf{path: strings.Join(ns, "."), names: ns, field: sf, index: idx})
+		}
+	}
+	walk(t, nil, nil)
+	return rv
+}
+
+// value returns the field of the leaf in v, allocating nil struct pointers on the way.
+func (lf leaf) value(v reflect.Value) reflect.Value {
+	fv, _ := reflectx.FieldByIndex(v, lf.index, true)
+	return fv
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// setString sets v from the text of an environment variable, a flag or a tag.
+// Slices take comma separated values.
+func setString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		var parts []string
+		if s = strings.TrimSpace(s); s != "" {
+			parts = strings.Split(s, ",")
+		}
+		sl := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := setString(sl.Index(i), strings.TrimSpace(p)); err != nil {
+				return err
+			}
+		}
+		v.Set(sl)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Layer names reported in Sources.
+const (
+	LayerDefault = "default"
+	LayerFile    = "file"
+	LayerEnv     = "env"
+	LayerFlag    = "flag"
+)
+
+// Sources tells which layer each value came from, keyed by dotted key path such as
+// "mysql.host". Values are "default", "file:<path>", "env:<NAME>" or "flag:<name>".
+type Sources map[string]string
+
+// String lists the sources sorted by key path, one "path = source" per line.
+func (s Sources) String() string {
+	var sb strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(s)) {
+		fmt.Fprintf(&sb, "%s = %s\n", k, s[k])
+	}
+	return sb.String()
+}
+
+// Loader merges configuration layers into a struct. Later layers override earlier ones:
+//
+//  1. defaults: the values of the struct before Load, then the default tags of
+//     the fields still zero, see ApplyDefaults
+//  2. Files, in order; each sets only the keys it contains
+//  3. environment variables
+//  4. command-line flags bound with BindFlags, when set explicitly
+//
+// Key paths join the toml, yaml or json tag names of nested fields with dots, or their
+// lowercased names. The environment variable of a value is its key path in upper case
+// with dots replaced by underscores, prefixed by EnvPrefix and an underscore, e.g.
+// APP_MYSQL_HOST, unless its field has an env tag naming the variable. Flags are named
+// by the key paths, e.g. -mysql.host. Slices take comma separated values in both.
+type Loader struct {
+	Files         []string // TOML, YAML or JSON files, by extension
+	IgnoreMissing bool     // skip the files that do not exist
+	EnvPrefix     string
+	// LookupEnv reads the environment, defaults to os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+
+	flags map[string]*flagValue // by key path
+}
+
+// NewLoader creates a Loader of files with the environment prefix envPrefix.
+func NewLoader(envPrefix string, files ...string) *Loader {
+	return &Loader{Files: files, EnvPrefix: envPrefix}
+}
+
+// flagValue records the text of a flag until Load applies it.
+type flagValue struct {
+	value string
+	set   bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+// BindFlags defines a flag in fs for each value of the struct pointed to by dst,
+// to be parsed before Load. The usage tag of a field is used as the flag usage.
+func (l *Loader) BindFlags(fs *flag.FlagSet, dst any) error {
+	t, err := structOf(dst)
+	if err != nil {
+		return err
+	}
+	if l.flags == nil {
+		l.flags = make(map[string]*flagValue)
+	}
+	for _, lf := range leaves(t.Type()) {
+		fv := &flagValue{}
+		usage := lf.field.Tag.Get("usage")
+		if usage == "" {
+			usage = "sets " + lf.path
+		}
+		fs.Var(fv, lf.path, usage)
+		l.flags[lf.path] = fv
+	}
+	return nil
+}
+
+func structOf(dst any) (reflect.Value, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("config: %T is not a pointer to a struct", dst)
+	}
+	return v.Elem(), nil
+}
+
+// Load applies the layers to the struct pointed to by dst, checks the result with
+// Validate, and returns the source of each value.
+func (l *Loader) Load(dst any) (Sources, error) {
+	v, err := structOf(dst)
+	if err != nil {
+		return nil, err
+	}
+	lfs := leaves(v.Type())
+	src := make(Sources, len(lfs))
+	for _, lf := range lfs {
+		src[lf.path] = LayerDefault
+	}
+	if err := ApplyDefaults(dst); err != nil {
+		return src, err
+	}
+
+	for _, name := range l.Files {
+		if err := l.loadFile(name, dst, lfs, src); err != nil {
+			if l.IgnoreMissing && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return src, err
+		}
+	}
+
+	lookup := l.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	for _, lf := range lfs {
+		name := l.envName(lf)
+		s, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setString(lf.value(v), s); err != nil {
+			return src, fmt.Errorf("config: env %s: %w", name, err)
+		}
+		src[lf.path] = LayerEnv + ":" + name
+	}
+
+	for _, lf := range lfs {
+		fv, ok := l.flags[lf.path]
+		if !ok || !fv.set {
+			continue
+		}
+		if err := setString(lf.value(v), fv.value); err != nil {
+			return src, fmt.Errorf("config: flag -%s: %w", lf.path, err)
+		}
+		src[lf.path] = LayerFlag + ":" + lf.path
+	}
+	return src, Validate(dst)
+}
+
+func (l *Loader) envName(lf leaf) string {
+	if name := lf.field.Tag.Get("env"); name != "" {
+		return name
+	}
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(lf.path))
+	if l.EnvPrefix != "" {
+		name = l.EnvPrefix + "_" + name
+	}
+	return name
+}
+
+// loadFile decodes the file into dst, then records the keys it contains as their source.
+func (l *Loader) loadFile(name string, dst any, lfs []leaf, src Sources) error {
+	f, err := formatOf(name)
+	if err != nil {
+		return err
+	}
+	bs, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if err := f.decode(bs, dst); err != nil {
+		return fmt.Errorf("config: %s: %w", name, err)
+	}
+	var keys map[string]any
+	if err := f.decode(bs, &keys); err != nil {
+		return fmt.Errorf("config: %s: %w", name, err)
+	}
+	for _, lf := range lfs {
+		if hasKey(keys, fileKeys(reflect.TypeOf(dst).Elem(), lf.index, f)) {
+			src[lf.path] = LayerFile + ":" + name
+		}
+	}
+	return nil
+}
+
+// fileKeys returns the keys naming the field at index in files of format f.
+// Flattened embedded structs have no key.
+func fileKeys(t reflect.Type, index []int, f format) []string {
+	var keys []string
+	for _, x := range index {
+		t = derefType(t)
+		sf := t.Field(x)
+		t = sf.Type
+		if sf.Anonymous && isNested(sf.Type) && !hasKeyTag(sf) {
+			continue
+		}
+		keys = append(keys, keyName(sf, f.tag()))
+	}
+	return keys
+}
+
+// hasKey reports whether the nested key path is present in m.
+func hasKey(m map[string]any, keys []string) bool {
+	_, ok := lookupKey(m, keys)
+	return ok
+}
+
+// lookupKey returns the value at the nested key path in m. Keys match case-insensitively,
+// like the field names of the decoders.
+func lookupKey(m map[string]any, keys []string) (any, bool) {
+	for i, k := range keys {
+		v, ok := m[k]
+		if !ok {
+			for mk, mv := range m {
+				if strings.EqualFold(mk, k) {
+					v, ok = mv, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		if i == len(keys)-1 {
+			return v, true
+		}
+		if m, ok = v.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
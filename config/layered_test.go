@@ -0,0 +1,109 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+type appConf struct {
+	Name    string        `toml:"name" yaml:"name" json:"name"`
+	Workers int           `toml:"workers" yaml:"workers" json:"workers"`
+	Timeout time.Duration `toml:"timeout" yaml:"timeout" json:"timeout"`
+	Tags    []string      `toml:"tags" yaml:"tags" json:"tags"`
+	Token   string        `toml:"token" env:"API_TOKEN"`
+	MySQL   MySQLConf     `toml:"mysql" yaml:"mysql" json:"mysql"`
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoader(t *testing.T) {
+	dir := t.TempDir()
+	tomlFile := writeFile(t, dir, "base.toml", `
+name = "base"
+workers = 2
+[mysql]
+host = "db.local"
+port = 3306
+`)
+	yamlFile := writeFile(t, dir, "prod.yaml", `
+workers: 8
+timeout: 1s
+mysql:
+  port: 3307
+  user: app
+`)
+	jsonFile := writeFile(t, dir, "local.json", `{"mysql": {"db": "orders"}}`)
+
+	env := map[string]string{"APP_MYSQL_PASSWORD": "secret", "APP_TAGS": "a, b", "API_TOKEN": "t0k", "APP_WORKERS": "16"}
+	l := NewLoader("APP", tomlFile, yamlFile, jsonFile, filepath.Join(dir, "missing.toml"))
+	l.IgnoreMissing = true
+	l.LookupEnv = func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+	conf := appConf{Name: "default", Timeout: time.Minute}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := l.BindFlags(fs, &conf); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-workers", "32", "-mysql.host=127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	src, err := l.Load(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := appConf{
+		Name: "base", Workers: 32, Timeout: time.Second, Tags: []string{"a", "b"}, Token: "t0k",
+		MySQL: MySQLConf{Host: "127.0.0.1", Port: 3307, User: "app", Password: "secret", DB: "orders",
+			Timeout: 1, ReadTimeout: 5, WriteTimeout: 5, MaxConn: 100},
+	}
+	if conf.Name != want.Name || conf.Workers != want.Workers || conf.Timeout != want.Timeout ||
+		!slices.Equal(conf.Tags, want.Tags) || conf.Token != want.Token || conf.MySQL != want.MySQL {
+		t.Errorf("Load() =\n%+v\nwant\n%+v", conf, want)
+	}
+	for path, layer := range map[string]string{
+		"name":           "file:" + tomlFile,
+		"timeout":        "file:" + yamlFile,
+		"mysql.port":     "file:" + yamlFile,
+		"mysql.db":       "file:" + jsonFile,
+		"mysql.password": "env:APP_MYSQL_PASSWORD",
+		"token":          "env:API_TOKEN",
+		"workers":        "flag:workers",
+		"mysql.host":     "flag:mysql.host",
+		"mysql.max_conn": "default",
+	} {
+		if src[path] != layer {
+			t.Errorf("source of %s = %q, want %q", path, src[path], layer)
+		}
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	var conf appConf
+	if _, err := NewLoader("", filepath.Join(t.TempDir(), "missing.toml")).Load(&conf); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+	if _, err := NewLoader("", "conf.ini").Load(&conf); err == nil {
+		t.Error("Load() of an unknown format succeeded")
+	}
+	l := &Loader{LookupEnv: func(k string) (string, bool) { return "many", k == "WORKERS" }}
+	if _, err := l.Load(&conf); err == nil {
+		t.Error("Load() of an invalid env value succeeded")
+	}
+	if _, err := l.Load(conf); err == nil {
+		t.Error("Load() of a non-pointer succeeded")
+	}
+}
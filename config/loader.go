@@ -1,17 +1,139 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
 // LoadTOML load toml file to obj
+// collections.Set and collections.Counter decode from TOML arrays and tables,
+// as well as from strings, and so do other types implementing json.Unmarshaler
+// and encoding.TextUnmarshaler.
+//
+// Like the other loaders, when obj points to a struct, the default tags of its zero fields
+// are applied before decoding and the validate tags are checked after, see Validate.
 func LoadTOML(filename string, obj any) error {
+	return load(filename, obj, formatTOML)
+}
+
+// LoadYAML load yaml file to obj
+func LoadYAML(filename string, obj any) error {
+	return load(filename, obj, formatYAML)
+}
+
+// LoadJSON load json file to obj
+func LoadJSON(filename string, obj any) error {
+	return load(filename, obj, formatJSON)
+}
+
+// LoadFile loads a TOML, YAML or JSON file to obj, depending on its extension:
+// .toml, .yaml or .yml, or .json.
+func LoadFile(filename string, obj any) error {
+	f, err := formatOf(filename)
+	if err != nil {
+		return err
+	}
+	return load(filename, obj, f)
+}
+
+func load(filename string, obj any, f format) error {
 	bs, err := os.ReadFile(filename)
 	if err != nil {
 		return err
 	}
-	err = toml.Unmarshal(bs, obj)
-	return err
+	if err := ApplyDefaults(obj); err != nil {
+		return err
+	}
+	if err := f.decode(bs, obj); err != nil {
+		return err
+	}
+	if _, err := structOf(obj); err != nil {
+		return nil
+	}
+	return Validate(obj)
+}
+
+// decodeTOML decodes bs into obj. go-toml hands only strings to TextUnmarshaler types,
+// so the values implementing json.Unmarshaler, such as collections.Set and collections.Counter,
+// are then decoded again from their native TOML arrays and tables, through JSON.
+func decodeTOML(bs []byte, obj any) error {
+	if err := toml.Unmarshal(bs, obj); err != nil {
+		return err
+	}
+	v, err := structOf(obj)
+	if err != nil {
+		return nil
+	}
+	var keys map[string]any
+	if err := toml.Unmarshal(bs, &keys); err != nil {
+		return err
+	}
+	for _, lf := range leaves(v.Type()) {
+		if !reflect.PointerTo(derefType(lf.field.Type)).Implements(jsonUnmarshalerType) {
+			continue
+		}
+		raw, ok := lookupKey(keys, fileKeys(v.Type(), lf.index, formatTOML))
+		if _, isString := raw.(string); !ok || isString {
+			continue
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("config: %s: %w", lf.path, err)
+		}
+		fv := lf.value(v)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+		} else {
+			fv = fv.Addr()
+		}
+		if err := fv.Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("config: %s: %w", lf.path, err)
+		}
+	}
+	return nil
+}
+
+// format is a configuration file format.
+type format string
+
+const (
+	formatTOML format = "toml"
+	formatYAML format = "yaml"
+	formatJSON format = "json"
+)
+
+func formatOf(filename string) (format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".toml":
+		return formatTOML, nil
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	case ".json":
+		return formatJSON, nil
+	}
+	return "", fmt.Errorf("config: unknown format of %s", filename)
+}
+
+func (f format) decode(bs []byte, obj any) error {
+	switch f {
+	case formatTOML:
+		return decodeTOML(bs, obj)
+	case formatYAML:
+		return yaml.Unmarshal(bs, obj)
+	}
+	return json.Unmarshal(bs, obj)
+}
+
+// tag is the struct tag naming the keys of the format.
+func (f format) tag() string {
+	return string(f)
 }
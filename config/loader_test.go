@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chenyan/wheels/collections"
+)
+
+func TestLoadTOML_Collections(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "conf.toml")
+	content := `
+tags = ["a", "b", "a"]
+ids = "3, 1"
+weights = { go = 3, rust = 1 }
+`
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var conf struct {
+		Tags    collections.Set[string]     `toml:"tags"`
+		IDs     collections.Set[int]        `toml:"ids"`
+		Weights collections.Counter[string] `toml:"weights"`
+	}
+	if err := LoadTOML(filename, &conf); err != nil {
+		t.Fatalf("LoadTOML() error = %v", err)
+	}
+	if conf.Tags.Len() != 2 || !conf.Tags.Contains("b") {
+		t.Errorf("tags = %v", conf.Tags.ToSlice())
+	}
+	if conf.IDs.Len() != 2 || !conf.IDs.Contains(3) {
+		t.Errorf("ids = %v", conf.IDs.ToSlice())
+	}
+	if conf.Weights.Get("go") != 3 || conf.Weights.Get("rust") != 1 {
+		t.Errorf("weights = %v", conf.Weights.Items())
+	}
+}
+
+func TestLoadTOML_CollectionsTable(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "conf.toml")
+	content := `
+[app]
+tags = ["x"]
+
+[app.weights]
+go = 3
+`
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var conf struct {
+		App struct {
+			Tags    *collections.Set[string]    `toml:"tags"`
+			Weights collections.Counter[string] `toml:"weights"`
+		} `toml:"app"`
+	}
+	if err := LoadTOML(filename, &conf); err != nil {
+		t.Fatalf("LoadTOML() error = %v", err)
+	}
+	if conf.App.Tags == nil || !conf.App.Tags.Contains("x") {
+		t.Errorf("app.tags = %v", conf.App.Tags)
+	}
+	if conf.App.Weights.Get("go") != 3 {
+		t.Errorf("app.weights = %v", conf.App.Weights.Items())
+	}
+}
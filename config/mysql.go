@@ -11,50 +11,60 @@ import (
 
 const (
 	MySQLMaxLifetime         = 59 * time.Second
+	MySQLDefaultPort         = 3306
 	MySQLDefaultMaxConn      = 100
 	MySQLDefaultTimeout      = 1
 	MySQLDefaultReadTimeout  = 5
 	MySQLDefaultWriteTimeout = 5
 )
 
+// MySQLConf configures a MySQL connection. Port, timeouts and MaxConn take the values
+// of their default tags, the MySQLDefault constants, when they are zero after loading
+// by this package, and when they are <= 0 in DSN, Gen and GenSession, which leave
+// the receiver untouched.
 type MySQLConf struct {
-	Host         string `toml:"host" yaml:"host"`
-	Port         int    `toml:"port" yaml:"port"`
+	Host         string `toml:"host" yaml:"host" validate:"required"`
+	Port         int    `toml:"port" yaml:"port" default:"3306" validate:"min=1,max=65535"`
 	DB           string `toml:"db" yaml:"db"`
 	User         string `toml:"user" yaml:"user"`
 	Password     string `toml:"password" yaml:"password"`
-	Timeout      int    `toml:"timeout" yaml:"timeout"`
-	ReadTimeout  int    `toml:"read_timeout" yaml:"read_timeout"`
-	WriteTimeout int    `toml:"write_timeout" yaml:"write_timeout"`
-	MaxConn      int    `toml:"max_conn" yaml:"max_conn"`
+	Timeout      int    `toml:"timeout" yaml:"timeout" default:"1" validate:"min=1"`
+	ReadTimeout  int    `toml:"read_timeout" yaml:"read_timeout" default:"5" validate:"min=1"`
+	WriteTimeout int    `toml:"write_timeout" yaml:"write_timeout" default:"5" validate:"min=1"`
+	MaxConn      int    `toml:"max_conn" yaml:"max_conn" default:"100" validate:"min=1"`
 }
 
-func (conf *MySQLConf) DSN() string {
-	if conf.Timeout <= 0 {
-		conf.Timeout = MySQLDefaultTimeout
-	}
-	if conf.ReadTimeout <= 0 {
-		conf.ReadTimeout = MySQLDefaultReadTimeout
-	}
-	if conf.WriteTimeout <= 0 {
-		conf.WriteTimeout = MySQLDefaultWriteTimeout
+// withDefaults returns a copy of conf with the default tags applied to the values <= 0.
+func (conf *MySQLConf) withDefaults() MySQLConf {
+	c := *conf
+	for _, v := range []*int{&c.Port, &c.Timeout, &c.ReadTimeout, &c.WriteTimeout, &c.MaxConn} {
+		*v = max(*v, 0)
 	}
+	// the tags are constant and valid, see TestMySQLConf_Defaults
+	_ = ApplyDefaults(&c)
+	return c
+}
+
+// Validate checks conf with its defaults applied.
+func (conf *MySQLConf) Validate() error {
+	c := conf.withDefaults()
+	return Validate(&c)
+}
+
+func (conf *MySQLConf) DSN() string {
+	c := conf.withDefaults()
 	return fmt.Sprintf(
 		"%s:%s@tcp(%s:%d)/%s?timeout=%ds&readTimeout=%ds&writeTimeout=%ds&charset=utf8mb4&collation=utf8mb4_unicode_520_ci&parseTime=true&loc=Local",
-		conf.User, conf.Password, conf.Host, conf.Port, conf.DB, conf.Timeout, conf.ReadTimeout, conf.WriteTimeout)
+		c.User, c.Password, c.Host, c.Port, c.DB, c.Timeout, c.ReadTimeout, c.WriteTimeout)
 }
 
 func (conf *MySQLConf) Gen() (*sql.DB, error) {
-	dsn := conf.DSN()
-	db, err := sql.Open("mysql", dsn)
+	db, err := sql.Open("mysql", conf.DSN())
 	if err != nil {
 		return nil, err
 	}
 	db.SetConnMaxLifetime(MySQLMaxLifetime)
-	if conf.MaxConn <= 0 {
-		conf.MaxConn = MySQLDefaultMaxConn
-	}
-	db.SetMaxOpenConns(conf.MaxConn)
+	db.SetMaxOpenConns(conf.withDefaults().MaxConn)
 	return db, nil
 }
 
@@ -68,10 +78,7 @@ func (conf *MySQLConf) GenSession() (db.Session, error) {
 		return nil, err
 	}
 	r.SetConnMaxLifetime(MySQLMaxLifetime)
-	if conf.MaxConn <= 0 {
-		conf.MaxConn = MySQLDefaultMaxConn
-	}
-	r.SetMaxOpenConns(conf.MaxConn)
+	r.SetMaxOpenConns(conf.withDefaults().MaxConn)
 	return r, nil
 }
 
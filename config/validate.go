@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError is a field failing a rule of its validate tag.
+type FieldError struct {
+	Path string // key path, with indexes for slice elements, e.g. servers[1].port
+	Rule string // e.g. required or min=1
+	Msg  string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// ValidationErrors are all the validation failures of a struct.
+type ValidationErrors []*FieldError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return "config: invalid " + strings.Join(msgs, "; ")
+}
+
+// walkFields calls fn for each field of the struct v, recursing into nested structs,
+// including the elements of slices, arrays and maps of structs. Nil pointers are not followed.
+func walkFields(v reflect.Value, path string, fn func(path string, sf reflect.StructField, fv reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		p := path
+		if !(sf.Anonymous && isNested(sf.Type) && !hasKeyTag(sf)) {
+			name := keyName(sf)
+			if name == "" {
+				continue
+			}
+			p = joinPath(path, name)
+		}
+		fn(p, sf, fv)
+		walkValue(fv, p, fn)
+	}
+}
+
+func walkValue(v reflect.Value, path string, fn func(path string, sf reflect.StructField, fv reflect.Value)) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if isNested(v.Type()) {
+			walkFields(v, path, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		if isNested(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				walkValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn)
+			}
+		}
+	case reflect.Map:
+		if isNested(v.Type().Elem()) {
+			for it := v.MapRange(); it.Next(); {
+				walkValue(it.Value(), fmt.Sprintf("%s[%v]", path, it.Key()), fn)
+			}
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// ApplyDefaults sets the zero fields of the struct pointed to by dst to the value of
+// their default tag, e.g. `default:"5"` or `default:"1s"` or `default:"a,b"` for a slice.
+// Fields of structs held in maps are left alone, as they cannot be set in place.
+// A dst that is not a pointer to a struct is left untouched.
+func ApplyDefaults(dst any) error {
+	v, err := structOf(dst)
+	if err != nil {
+		return nil
+	}
+	var errs ValidationErrors
+	walkFields(v, "", func(path string, sf reflect.StructField, fv reflect.Value) {
+		def, ok := sf.Tag.Lookup("default")
+		if !ok || !fv.CanSet() || !fv.IsZero() {
+			return
+		}
+		if err := setString(fv, def); err != nil {
+			errs = append(errs, &FieldError{Path: path, Rule: "default", Msg: fmt.Sprintf("invalid default %q: %v", def, err)})
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks the struct pointed to by v, or the struct v, against the validate
+// tags of its fields and returns ValidationErrors listing every failure. Rules are
+// separated by commas:
+//
+//	required     the value is not zero
+//	min=N, max=N bounds of a number, or of the length of a string, slice or map;
+//	             N may be a duration such as 1s for time.Duration fields
+//	oneof=a b c  the value, formatted as text, is one of the space separated values
+//
+// Rules other than required are skipped for zero values, so that optional fields
+// may stay empty.
+func Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("config: %T is not a struct", v)
+	}
+	var errs ValidationErrors
+	walkFields(rv, "", func(path string, sf reflect.StructField, fv reflect.Value) {
+		tag := sf.Tag.Get("validate")
+		if tag == "" {
+			return
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if msg := check(fv, strings.TrimSpace(rule)); msg != "" {
+				errs = append(errs, &FieldError{Path: path, Rule: rule, Msg: msg})
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// check returns why v fails rule, or "" if it passes.
+func check(v reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	if name == "required" {
+		if v.IsZero() {
+			return "is required"
+		}
+		return ""
+	}
+	if v.IsZero() {
+		return ""
+	}
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch name {
+	case "min", "max":
+		n, unit, ok := measure(v)
+		if !ok {
+			return fmt.Sprintf("rule %s does not apply to %s", name, v.Type())
+		}
+		bound, err := parseBound(arg, v.Type())
+		if err != nil {
+			return fmt.Sprintf("invalid rule %s: %v", rule, err)
+		}
+		if name == "min" && n < bound {
+			return fmt.Sprintf("%s %s is less than %s", unit, display(v, n), arg)
+		}
+		if name == "max" && n > bound {
+			return fmt.Sprintf("%s %s is greater than %s", unit, display(v, n), arg)
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		if !slices.Contains(strings.Fields(arg), s) {
+			return fmt.Sprintf("%q is not one of %s", s, arg)
+		}
+	default:
+		return fmt.Sprintf("unknown rule %q", name)
+	}
+	return ""
+}
+
+// measure returns the number compared by min and max: the value of numbers,
+// the length of strings, slices and maps.
+func measure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "value", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), "value", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "value", true
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), "length", true
+	}
+	return 0, "", false
+}
+
+func parseBound(arg string, t reflect.Type) (float64, error) {
+	if t == durationType {
+		if d, err := time.ParseDuration(arg); err == nil {
+			return float64(d), nil
+		}
+	}
+	return strconv.ParseFloat(arg, 64)
+}
+
+func display(v reflect.Value, n float64) string {
+	if v.Type() == durationType {
+		return time.Duration(n).String()
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
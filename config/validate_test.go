@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type serverConf struct {
+	Host string `toml:"host" validate:"required"`
+	Port int    `toml:"port" default:"8080" validate:"min=1,max=65535"`
+}
+
+type validatedConf struct {
+	Mode     string        `toml:"mode" default:"prod" validate:"oneof=dev prod"`
+	Interval time.Duration `toml:"interval" default:"5s" validate:"min=1s,max=1m"`
+	Tags     []string      `toml:"tags" default:"a,b" validate:"max=3"`
+	Name     string        `toml:"name" validate:"min=3"`
+	Servers  []serverConf  `toml:"servers"`
+	Primary  *serverConf   `toml:"primary"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	c := validatedConf{Mode: "dev", Servers: []serverConf{{Host: "a"}, {Host: "b", Port: 9}}}
+	if err := ApplyDefaults(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Mode != "dev" || c.Interval != 5*time.Second || len(c.Tags) != 2 {
+		t.Errorf("ApplyDefaults() = %+v", c)
+	}
+	if c.Servers[0].Port != 8080 || c.Servers[1].Port != 9 || c.Primary != nil {
+		t.Errorf("ApplyDefaults() servers = %+v, primary = %v", c.Servers, c.Primary)
+	}
+
+	var bad struct {
+		N int `default:"many"`
+	}
+	var verrs ValidationErrors
+	if err := ApplyDefaults(&bad); !errors.As(err, &verrs) || verrs[0].Path != "n" {
+		t.Errorf("ApplyDefaults() of an invalid default = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := validatedConf{
+		Mode:     "staging",
+		Interval: time.Hour,
+		Tags:     []string{"a", "b", "c", "d"},
+		Name:     "ab",
+		Servers:  []serverConf{{Host: "a", Port: 1}, {Port: 70000}},
+		Primary:  &serverConf{Port: 1},
+	}
+	err := Validate(&c)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Validate() = %v, want ValidationErrors", err)
+	}
+	got := map[string]string{}
+	for _, e := range verrs {
+		got[e.Path] = e.Rule
+	}
+	want := map[string]string{
+		"mode":            "oneof=dev prod",
+		"interval":        "max=1m",
+		"tags":            "max=3",
+		"name":            "min=3",
+		"servers[1].host": "required",
+		"servers[1].port": "max=65535",
+		"primary.host":    "required",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Validate() = %v", err)
+	}
+	for path, rule := range want {
+		if got[path] != rule {
+			t.Errorf("failure of %s = %q, want %q in %v", path, got[path], rule, err)
+		}
+	}
+	if !strings.Contains(err.Error(), "servers[1].port: value 70000 is greater than 65535") {
+		t.Errorf("Error() = %s", err)
+	}
+
+	ok := validatedConf{Mode: "dev", Interval: time.Second}
+	if err := Validate(ok); err != nil {
+		t.Errorf("Validate() of a valid struct = %v", err)
+	}
+}
+
+func TestLoadTOML_DefaultsAndValidation(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.toml")
+	os.WriteFile(valid, []byte("name = \"orders\"\n[[servers]]\nhost = \"a\"\n"), 0o644)
+	var c validatedConf
+	if err := LoadTOML(valid, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Mode != "prod" || c.Interval != 5*time.Second {
+		t.Errorf("LoadTOML() = %+v", c)
+	}
+
+	invalid := filepath.Join(dir, "invalid.toml")
+	os.WriteFile(invalid, []byte("mode = \"test\"\n"), 0o644)
+	if err := LoadTOML(invalid, &validatedConf{}); err == nil {
+		t.Error("LoadTOML() of an invalid file succeeded")
+	}
+}
+
+func TestMySQLConf_Defaults(t *testing.T) {
+	conf := &MySQLConf{Host: "db", User: "u", DB: "d", Timeout: -1}
+	dsn := conf.DSN()
+	if *conf != (MySQLConf{Host: "db", User: "u", DB: "d", Timeout: -1}) {
+		t.Errorf("DSN() modified the receiver: %+v", conf)
+	}
+	if !strings.HasPrefix(dsn, "u:@tcp(db:3306)/d?timeout=1s&readTimeout=5s&writeTimeout=5s&") {
+		t.Errorf("DSN() = %s", dsn)
+	}
+	if err := conf.Validate(); err != nil {
+		t.Errorf("Validate() of a conf DSN accepts = %v", err)
+	}
+	c := (&MySQLConf{}).withDefaults()
+	if c.Port != MySQLDefaultPort || c.Timeout != MySQLDefaultTimeout || c.ReadTimeout != MySQLDefaultReadTimeout ||
+		c.WriteTimeout != MySQLDefaultWriteTimeout || c.MaxConn != MySQLDefaultMaxConn {
+		t.Errorf("default tags do not match the MySQLDefault constants: %+v", c)
+	}
+	if db, err := (&MySQLConf{}).Gen(); err != nil {
+		t.Errorf("Gen() error = %v, want it to leave validation to the loaders", err)
+	} else {
+		db.Close()
+	}
+	if err := (&MySQLConf{Port: 70000}).Validate(); err == nil || !strings.Contains(err.Error(), "host: is required") {
+		t.Errorf("Validate() = %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chenyan/wheels/funcs"
+)
+
+// WatchOpts configures Watch.
+type WatchOpts struct {
+	PollInterval time.Duration // defaults to 2s
+	// OnError receives the errors of rejected reloads, while the previous config stays in effect.
+	OnError func(err error)
+}
+
+// Watcher keeps the config of a Loader up to date with its files. It polls the files,
+// loads a new *T when their content changes, and swaps it in if it validates.
+// The snapshots returned by Get are shared and must not be modified.
+type Watcher[T any] struct {
+	loader *Loader
+	opts   WatchOpts
+
+	cur     atomic.Pointer[T]
+	sources atomic.Pointer[Sources]
+	lastErr atomic.Pointer[error]
+
+	reloadMu sync.Mutex // serializes reloads
+	sums     map[string][sha256.Size]byte
+
+	mu   sync.Mutex
+	subs map[int]func(old, new *T)
+	next int
+}
+
+// Watch loads the config with l and reloads it whenever the files of l change,
+// until ctx is done. It fails if the initial load fails.
+func Watch[T any](ctx context.Context, l *Loader, opts *WatchOpts) (*Watcher[T], error) {
+	w := &Watcher[T]{loader: l, subs: make(map[int]func(old, new *T))}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.PollInterval <= 0 {
+		w.opts.PollInterval = 2 * time.Second
+	}
+	w.sums = w.checksums()
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	go w.poll(ctx)
+	return w, nil
+}
+
+// Get returns the current config.
+func (w *Watcher[T]) Get() *T {
+	return w.cur.Load()
+}
+
+// Sources returns the sources of the current config.
+func (w *Watcher[T]) Sources() Sources {
+	return *w.sources.Load()
+}
+
+// Err returns the error of the last reload, nil if it succeeded.
+func (w *Watcher[T]) Err() error {
+	if err := w.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Subscribe registers fn to be called with the old and new config after each successful
+// reload, and returns a function to unregister it. Subscribers are called one at a time
+// in the order they subscribed, on the goroutine of the reload: fn must not block,
+// since a slow subscriber delays the next reloads. A panic in fn is recovered
+// and does not affect the other subscribers.
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) (cancel func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.next
+	w.next++
+	w.subs[id] = fn
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subs, id)
+	}
+}
+
+// Reload loads the config now, whether or not the files changed. On error the
+// current config is kept.
+func (w *Watcher[T]) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	w.sums = w.checksums()
+	return w.reload()
+}
+
+func (w *Watcher[T]) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		w.reloadMu.Lock()
+		if sums := w.checksums(); !maps.Equal(sums, w.sums) {
+			w.sums = sums
+			if err := w.reload(); err != nil && w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+		}
+		w.reloadMu.Unlock()
+	}
+}
+
+// reload loads a new config and notifies the subscribers. Callers hold reloadMu.
+func (w *Watcher[T]) reload() error {
+	old := w.cur.Load()
+	if err := w.load(); err != nil {
+		w.lastErr.Store(&err)
+		return err
+	}
+	w.lastErr.Store(nil)
+
+	w.mu.Lock()
+	subs := make([]func(old, new *T), 0, len(w.subs))
+	for _, id := range slices.Sorted(maps.Keys(w.subs)) {
+		subs = append(subs, w.subs[id])
+	}
+	w.mu.Unlock()
+
+	cur := w.cur.Load()
+	for _, fn := range subs {
+		funcs.F(func() { fn(old, cur) })
+	}
+	return nil
+}
+
+// load swaps in a new config if it loads and validates.
+func (w *Watcher[T]) load() error {
+	dst := new(T)
+	src, err := w.loader.Load(dst)
+	if err != nil {
+		return err
+	}
+	w.cur.Store(dst)
+	w.sources.Store(&src)
+	return nil
+}
+
+// checksums hashes the content of the files, leaving out the ones that cannot be read.
+func (w *Watcher[T]) checksums() map[string][sha256.Size]byte {
+	sums := make(map[string][sha256.Size]byte, len(w.loader.Files))
+	for _, name := range w.loader.Files {
+		bs, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		sums[name] = sha256.Sum256(bs)
+	}
+	return sums
+}
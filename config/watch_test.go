@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type watchedConf struct {
+	Name    string `yaml:"name" validate:"required"`
+	Workers int    `yaml:"workers" default:"4" validate:"min=1"`
+}
+
+func TestWatch(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.yaml")
+	write := func(s string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("name: a\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 10)
+	w, err := Watch[watchedConf](ctx, NewLoader("WATCH_TEST", name), &WatchOpts{
+		PollInterval: 5 * time.Millisecond,
+		OnError:      func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := w.Get(); c.Name != "a" || c.Workers != 4 {
+		t.Fatalf("Get() = %+v", c)
+	}
+
+	var mu sync.Mutex
+	var changes [][2]watchedConf
+	changed := make(chan struct{}, 10)
+	w.Subscribe(func(old, new *watchedConf) {
+		mu.Lock()
+		changes = append(changes, [2]watchedConf{*old, *new})
+		mu.Unlock()
+		changed <- struct{}{}
+	})
+
+	write("name: b\nworkers: 8\n")
+	waitChange(t, changed)
+	mu.Lock()
+	if len(changes) != 1 || changes[0][0].Name != "a" || changes[0][1] != (watchedConf{"b", 8}) {
+		t.Errorf("changes = %+v", changes)
+	}
+	mu.Unlock()
+	if w.Sources()["workers"] != "file:"+name {
+		t.Errorf("Sources() = %v", w.Sources())
+	}
+
+	write("workers: 16\n")
+	select {
+	case err := <-errs:
+		if err == nil || w.Err() == nil {
+			t.Errorf("OnError(%v), Err() = %v", err, w.Err())
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no error for an invalid file")
+	}
+	if c := w.Get(); *c != (watchedConf{"b", 8}) {
+		t.Errorf("Get() after an invalid file = %+v", c)
+	}
+
+	write("name: d\n")
+	waitChange(t, changed)
+	if c := w.Get(); *c != (watchedConf{"d", 4}) || w.Err() != nil {
+		t.Errorf("Get() = %+v, Err() = %v", c, w.Err())
+	}
+}
+
+func TestWatch_Invalid(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.yaml")
+	os.WriteFile(name, []byte("workers: 2\n"), 0o644)
+	if _, err := Watch[watchedConf](context.Background(), NewLoader("WATCH_TEST", name), nil); err == nil {
+		t.Error("Watch() of an invalid file succeeded")
+	}
+}
+
+func TestWatch_SubscriberPanic(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.yaml")
+	os.WriteFile(name, []byte("name: a\n"), 0o644)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := Watch[watchedConf](ctx, NewLoader("WATCH_TEST", name), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan struct{}, 1)
+	w.Subscribe(func(old, new *watchedConf) { panic("subscriber bug") })
+	w.Subscribe(func(old, new *watchedConf) { changed <- struct{}{} })
+	os.WriteFile(name, []byte("name: b\n"), 0o644)
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, changed)
+	if w.Get().Name != "b" {
+		t.Errorf("Get() = %+v", w.Get())
+	}
+}
+
+func waitChange(t *testing.T, changed <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reload after the file changed")
+	}
+}
@@ -1,5 +1,7 @@
 package maps
 
+import "github.com/chenyan/wheels/codec/jsonx"
+
 // GetOr returns the value of the key in the map if it exists, otherwise it returns the default value.
 func GetOr[T any](m map[string]any, key string, defval T) T {
 	if v, ok := m[key]; ok {
@@ -9,3 +11,10 @@ func GetOr[T any](m map[string]any, key string, defval T) T {
 	}
 	return defval
 }
+
+// GetPath is GetOr for nested values: path is a JSONPath such as "data[0].instId"
+// or "a.b.c", see jsonx.Path. Numbers convert between numeric types when no precision
+// is lost, so a JSON number decoded as float64 can be read as an int.
+func GetPath[T any](m map[string]any, path string, defval T) T {
+	return jsonx.GetOr(m, path, defval)
+}
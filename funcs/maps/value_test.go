@@ -49,3 +49,22 @@ func TestGetOr(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPath(t *testing.T) {
+	m := map[string]any{
+		"code": "0",
+		"data": []any{map[string]any{"instId": "BTC-USDT", "lotSz": 0.001, "lever": float64(10)}},
+	}
+	if got := GetPath(m, "data[0].instId", ""); got != "BTC-USDT" {
+		t.Errorf("GetPath(data[0].instId) = %q, want BTC-USDT", got)
+	}
+	if got := GetPath(m, "data[0].lever", 0); got != 10 {
+		t.Errorf("GetPath(data[0].lever) = %d, want 10", got)
+	}
+	if got := GetPath(m, "data[0].lotSz", -1); got != -1 {
+		t.Errorf("GetPath(data[0].lotSz) as int = %d, want the default", got)
+	}
+	if got := GetPath(m, "data[1].instId", "none"); got != "none" {
+		t.Errorf("GetPath(data[1].instId) = %q, want none", got)
+	}
+}
@@ -1,6 +1,7 @@
 package funcs
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"runtime"
@@ -12,18 +13,39 @@ var (
 	StackSize = 2048
 )
 
+// PanicError is a recovered panic turned into an error.
+type PanicError struct {
+	Value any
+	Stack []byte // only set when ShowStack is true
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", e.Value)
+}
+
 // F is a wrapper for func() to recover from panic
 func F(f func()) {
+	E(func() error {
+		f()
+		return nil
+	})
+}
+
+// E is like F for functions returning an error: a panic is logged the same way
+// and returned as a *PanicError.
+func E(f func() error) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
+			pe := &PanicError{Value: r}
 			if ShowStack {
 				buf := make([]byte, StackSize)
-				n := runtime.Stack(buf, false)
-				Errlogger.Printf("recovered from panic: %v\n%s", r, buf[:n])
+				pe.Stack = buf[:runtime.Stack(buf, false)]
+				Errlogger.Printf("recovered from panic: %v\n%s", r, pe.Stack)
 			} else {
 				Errlogger.Printf("recovered from panic: %v", r)
 			}
+			err = pe
 		}
 	}()
-	f()
+	return f()
 }
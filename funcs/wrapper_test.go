@@ -2,6 +2,7 @@
 package funcs_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/chenyan/wheels/funcs"
@@ -23,3 +24,18 @@ func TestF(t *testing.T) {
 }
 
 // END: 8f7e2c1d3b4a
+
+func TestE(t *testing.T) {
+	err := funcs.E(func() error {
+		panic("test panic")
+	})
+	var pe *funcs.PanicError
+	if !errors.As(err, &pe) || pe.Value != "test panic" {
+		t.Errorf("E() error = %v, want *PanicError", err)
+	}
+
+	want := errors.New("plain")
+	if err := funcs.E(func() error { return want }); err != want {
+		t.Errorf("E() error = %v, want %v", err, want)
+	}
+}
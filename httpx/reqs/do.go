@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/chenyan/wheels/codec"
 )
 
 var (
@@ -33,10 +35,18 @@ func PostJSON2(url string, body any, opts *Opts) *Resp {
 	if err != nil {
 		return &Resp{Error: err}
 	}
-	if opts == nil {
-		opts = &Opts{}
+	opts = opts.withHeader("Content-Type", "application/json")
+	return Post2(url, bytes.NewReader(bs), opts)
+}
+
+// PostBody performs an HTTP POST request with body encoded by c, e.g. codec.MsgPack,
+// and sets the Content-Type header to the content type of c.
+func PostBody(url string, body any, c codec.Codec, opts *Opts) *Resp {
+	bs, err := c.Marshal(body)
+	if err != nil {
+		return &Resp{Error: err}
 	}
-	opts.AddHeader("Content-Type", "application/json")
+	opts = opts.withHeader("Content-Type", c.ContentType())
 	return Post2(url, bytes.NewReader(bs), opts)
 }
 
@@ -57,10 +67,7 @@ func PostFiles2(url string, files map[string]string, opts *Opts) *Resp {
 	if err != nil {
 		return &Resp{Error: err}
 	}
-	if opts == nil {
-		opts = &Opts{}
-	}
-	opts.AddHeader("Content-Type", contentType)
+	opts = opts.withHeader("Content-Type", contentType)
 	return Post2(url, body, opts)
 }
 
@@ -69,9 +76,22 @@ func Do(method string, url string, body io.Reader, opts *Opts) *Resp {
 	if err != nil {
 		return &Resp{Error: err}
 	}
+	if opts != nil && opts.Limiter != nil {
+		if err := opts.Limiter.Wait(r.Context()); err != nil {
+			return &Resp{Error: err}
+		}
+	}
+	done := func(bool) {}
+	if opts != nil && opts.Breaker != nil {
+		if done, err = opts.Breaker.Allow(); err != nil {
+			return &Resp{Error: err}
+		}
+	}
 	resp, err := DefaultClient.Do(r)
 	if err != nil {
+		done(false)
 		return &Resp{Error: err}
 	}
+	done(!opts.isFailure(resp))
 	return &Resp{Response: *resp, Error: nil}
 }
@@ -1,12 +1,17 @@
 package reqs
 
 import (
+	"errors"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
+
+	"github.com/chenyan/wheels/cc"
+	"github.com/chenyan/wheels/codec"
 )
 
 func TestGet(t *testing.T) {
@@ -104,6 +109,41 @@ func TestPostJSON(t *testing.T) {
 	}
 }
 
+func TestPostBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", req.Header.Get("Content-Type"))
+		bs, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("Error reading request body: %v", err)
+		}
+		rw.Write(bs)
+	}))
+	defer server.Close()
+
+	opts := &Opts{Headers: map[string]string{"X-Trace": "1"}}
+	resp := PostBody(server.URL, map[string]any{"a": 1, "b": "B"}, codec.MsgPack, opts)
+	if resp.Error != nil {
+		t.Fatal(resp.Error)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/msgpack" {
+		t.Errorf("Expected msgpack content type, but got %q", ct)
+	}
+	var data struct {
+		A int    `json:"a"`
+		B string `json:"b"`
+	}
+	if err := resp.Decode(&data); err != nil {
+		t.Errorf("Error decoding msgpack: %v", err)
+	}
+	if data.A != 1 || data.B != "B" {
+		t.Errorf("Expected data to be {1, \"B\"}, but got %v", data)
+	}
+	if len(opts.Headers) != 1 {
+		t.Errorf("PostBody() modified the opts headers: %v", opts.Headers)
+	}
+}
+
 func TestPostFiles(t *testing.T) {
 	// Start a local HTTP server
 	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -150,3 +190,55 @@ func TestPostFiles(t *testing.T) {
 	}
 	defer resp.Body.Close()
 }
+
+func TestDoBreaker(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		hits++
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	opts := &Opts{
+		Breaker: cc.NewBreaker(&cc.BreakerOpts{MinRequests: 2, CoolDown: time.Minute}),
+		Limiter: cc.NewLimiter(1000, 10),
+	}
+	for i := 0; i < 2; i++ {
+		if resp := Get2(server.URL, opts); resp.Error != nil {
+			t.Fatalf("Get2() error = %v", resp.Error)
+		}
+	}
+	if resp := Get2(server.URL, opts); !errors.Is(resp.Error, cc.ErrOpen) {
+		t.Errorf("Get2() error = %v, want cc.ErrOpen", resp.Error)
+	}
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2", hits)
+	}
+}
+
+func TestDoBreakerFailure(t *testing.T) {
+	status := http.StatusTooManyRequests
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	opts := &Opts{Breaker: cc.NewBreaker(&cc.BreakerOpts{MinRequests: 2, CoolDown: time.Minute})}
+	for i := 0; i < 2; i++ {
+		Get2(server.URL, opts)
+	}
+	if resp := Get2(server.URL, opts); !errors.Is(resp.Error, cc.ErrOpen) {
+		t.Errorf("Get2() after 429s error = %v, want cc.ErrOpen", resp.Error)
+	}
+
+	status = http.StatusServiceUnavailable
+	opts = &Opts{
+		Breaker: cc.NewBreaker(&cc.BreakerOpts{MinRequests: 2, CoolDown: time.Minute}),
+		Failure: func(resp *http.Response) bool { return false },
+	}
+	for i := 0; i < 3; i++ {
+		if resp := Get2(server.URL, opts); resp.Error != nil {
+			t.Fatalf("Get2() with a custom Failure error = %v", resp.Error)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -12,6 +13,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/chenyan/wheels/cc"
 )
 
 type Opts struct {
@@ -21,10 +24,31 @@ type Opts struct {
 	Query          url.Values
 	Stream         bool
 	AllowRedirects bool
+	// Limiter paces the request, Do waits for a token before sending it.
+	Limiter *cc.Limiter
+	// Breaker guards the request, Do fails fast with cc.ErrOpen while it is open.
+	// Transport errors and the responses Failure reports count as failures.
+	Breaker *cc.Breaker
+	// Failure tells whether a response counts as a failure of Breaker,
+	// defaults to IsFailure.
+	Failure func(resp *http.Response) bool
 
 	cookies []*http.Cookie
 }
 
+// IsFailure reports 5xx and 429 Too Many Requests responses as failures,
+// so that a throttling upstream opens the breaker.
+func IsFailure(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func (o *Opts) isFailure(resp *http.Response) bool {
+	if o == nil || o.Failure == nil {
+		return IsFailure(resp)
+	}
+	return o.Failure(resp)
+}
+
 // createForm creates a multipart/form-data body from a map of strings.
 // The keys are the field names, and the values are the field values.
 // If the value starts with "@", it is treated as a file path.
@@ -129,6 +153,17 @@ func Q(kv ...string) url.Values {
 	return values
 }
 
+// withHeader returns a copy of o with the header set, so that the opts of the caller
+// can be reused across requests. o may be nil.
+func (o *Opts) withHeader(key, value string) *Opts {
+	c := &Opts{}
+	if o != nil {
+		*c = *o
+	}
+	c.Headers = maps.Clone(c.Headers)
+	return c.AddHeader(key, value)
+}
+
 func (o *Opts) AddHeader(key, value string) *Opts {
 	if o.Headers == nil {
 		o.Headers = make(map[string]string)
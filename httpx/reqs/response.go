@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"github.com/chenyan/wheels/codec"
 )
 
 // Resp is the response of a request
@@ -19,6 +21,20 @@ func (r *Resp) JSON(v any) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
 
+// Decode decodes the body with the codec registered for the response Content-Type,
+// falling back to JSON when the header is missing or unknown.
+func (r *Resp) Decode(v any) error {
+	bs, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+	c, err := codec.ForContentType(r.Header.Get("Content-Type"))
+	if err != nil {
+		c = codec.JSON
+	}
+	return c.Unmarshal(bs, v)
+}
+
 func (r *Resp) JSONMap() (map[string]any, error) {
 	if r.Error != nil {
 		return nil, r.Error
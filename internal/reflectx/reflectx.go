@@ -0,0 +1,23 @@
+// Package reflectx holds the reflection helpers shared by the struct encoders
+// and the config loaders of this module.
+package reflectx
+
+import "reflect"
+
+// FieldByIndex is reflect.Value.FieldByIndex that reports nil embedded pointers
+// instead of panicking, or allocates them when alloc is set.
+func FieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,28 @@
+package reflectx
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Inner struct{ N int }
+
+type outer struct {
+	*Inner
+}
+
+func TestFieldByIndex(t *testing.T) {
+	var o outer
+	v := reflect.ValueOf(&o).Elem()
+	if _, ok := FieldByIndex(v, []int{0, 0}, false); ok || o.Inner != nil {
+		t.Errorf("FieldByIndex() without alloc = %v, inner = %v", ok, o.Inner)
+	}
+	fv, ok := FieldByIndex(v, []int{0, 0}, true)
+	if !ok || o.Inner == nil {
+		t.Fatalf("FieldByIndex() with alloc = %v, inner = %v", ok, o.Inner)
+	}
+	fv.SetInt(3)
+	if o.N != 3 {
+		t.Errorf("N = %d, want 3", o.N)
+	}
+}